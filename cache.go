@@ -0,0 +1,347 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores responses so that repeated requests aren't sent to the server. Keys are request URLs with the API key
+// removed. Implementations must be safe for concurrent use and must not return expired entries.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	Body    []byte
+	Header  http.Header
+	Expires time.Time
+}
+
+// DefaultCacheTTLs are how long each type of request is cached for. Types without a TTL, including "get" and
+// "details", are never cached; use WithCacheTTL to change them.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"caps":     24 * time.Hour,
+	"getnfo":   24 * time.Hour,
+	"search":   5 * time.Minute,
+	"tvsearch": 5 * time.Minute,
+	"movie":    5 * time.Minute,
+	"music":    5 * time.Minute,
+	"book":     5 * time.Minute,
+}
+
+// bypassCacheKey is the context key used by BypassCache.
+type bypassCacheKey struct{}
+
+// WithCache returns an Option that caches responses using the DefaultCacheTTLs.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL returns an Option that sets how long responses of type "t" are cached for. A zero TTL disables caching
+// of that type. Downloads are cached as "get".
+func WithCacheTTL(t string, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = make(map[string]time.Duration, len(DefaultCacheTTLs)+1)
+			for k, v := range DefaultCacheTTLs {
+				c.cacheTTLs[k] = v
+			}
+		}
+		c.cacheTTLs[t] = ttl
+	}
+}
+
+// BypassCache returns a context that makes a request skip the cache lookup and go to the server. The fresh response
+// is still stored.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// cacheKey returns the key of a request URL: the URL with the API key removed and the query parameters sorted.
+func cacheKey(u *url.URL) string {
+	q := u.Query()
+	q.Del("apikey")
+
+	k := *u
+	k.Scheme = strings.ToLower(k.Scheme)
+	k.Host = strings.ToLower(k.Host)
+	k.RawQuery = q.Encode()
+	k.Fragment = ""
+	return k.String()
+}
+
+// cacheTTL returns how long responses of type "t" are cached for.
+func (c *Client) cacheTTL(t string) time.Duration {
+	if c.cache == nil {
+		return 0
+	}
+	if c.cacheTTLs != nil {
+		return c.cacheTTLs[t]
+	}
+	return DefaultCacheTTLs[t]
+}
+
+// cached returns a copy of the cached response to a request of type "t", if there is one and the context allows it.
+// The copy means callers can't change the cached entry.
+func (c *Client) cached(ctx context.Context, u *url.URL, t string) (*CacheEntry, bool) {
+	if c.cacheTTL(t) <= 0 {
+		return nil, false
+	}
+	if bypass, _ := ctx.Value(bypassCacheKey{}).(bool); bypass {
+		return nil, false
+	}
+
+	entry, ok := c.cache.Get(cacheKey(u))
+	if !ok {
+		return nil, false
+	}
+	return entry.clone(), true
+}
+
+// store caches the response to a request of type "t".
+func (c *Client) store(u *url.URL, t string, body []byte, header http.Header) {
+	ttl := c.cacheTTL(t)
+	if ttl <= 0 {
+		return
+	}
+	entry := &CacheEntry{
+		Body:    body,
+		Header:  header,
+		Expires: time.Now().Add(ttl),
+	}
+	c.cache.Set(cacheKey(u), entry.clone())
+}
+
+// clone returns a copy of the entry that shares no memory with it.
+func (e *CacheEntry) clone() *CacheEntry {
+	body := make([]byte, len(e.Body))
+	copy(body, e.Body)
+	return &CacheEntry{
+		Body:    body,
+		Header:  e.Header.Clone(),
+		Expires: e.Expires,
+	}
+}
+
+// MemoryCache is a Cache that keeps up to a fixed number of entries in memory, discarding the least recently used.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// memoryEntry is an element of MemoryCache.order.
+type memoryEntry struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache that holds up to size entries.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the entry for a key if it hasn't expired.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*memoryEntry).entry
+	if time.Now().After(entry.Expires) {
+		m.remove(e)
+		return nil, false
+	}
+
+	m.order.MoveToFront(e)
+	return entry, true
+}
+
+// Set stores an entry, discarding the least recently used entry if the cache is full.
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*memoryEntry).entry = entry
+		m.order.MoveToFront(e)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, entry: entry})
+	for m.size > 0 && m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+}
+
+// Delete removes the entry for a key.
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		m.remove(e)
+	}
+}
+
+func (m *MemoryCache) remove(e *list.Element) {
+	delete(m.entries, e.Value.(*memoryEntry).key)
+	m.order.Remove(e)
+}
+
+// DiskCache is a Cache that keeps each entry in a file in a directory, so that entries survive restarts. Expired
+// entries are removed by Prune, which Set calls at most once an hour.
+type DiskCache struct {
+	dir string
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// diskPruneInterval is how often Set prunes a DiskCache.
+const diskPruneInterval = time.Hour
+
+// NewDiskCache returns a DiskCache that stores its entries in dir, creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get returns the entry for a key if it hasn't expired.
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	f, err := os.Open(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var entry CacheEntry
+	if err = gob.NewDecoder(f).Decode(&entry); err != nil {
+		return nil, false
+	}
+
+	if time.Now().After(entry.Expires) {
+		_ = os.Remove(f.Name())
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Set stores an entry. Errors are ignored, since a missing entry only means the request is sent to the server again.
+func (d *DiskCache) Set(key string, entry *CacheEntry) {
+	d.mu.Lock()
+	prune := time.Since(d.lastPrune) > diskPruneInterval
+	if prune {
+		d.lastPrune = time.Now()
+	}
+	d.mu.Unlock()
+	if prune {
+		_ = d.Prune()
+	}
+
+	// Write to a temporary file first so that readers never see a partial entry.
+	f, err := ioutil.TempFile(d.dir, "tmp-")
+	if err != nil {
+		return
+	}
+
+	err = gob.NewEncoder(f).Encode(entry)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	// The modification time records when the entry expires, so that Prune doesn't have to read every file.
+	if err == nil {
+		err = os.Chtimes(f.Name(), entry.Expires, entry.Expires)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// Delete removes the entry for a key.
+func (d *DiskCache) Delete(key string) {
+	_ = os.Remove(d.path(key))
+}
+
+// Prune removes the expired entries, and any temporary files left behind by an interrupted Set. Other files in the
+// directory are left alone.
+func (d *DiskCache) Prune() error {
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+
+		var expired bool
+		switch name := file.Name(); {
+		case strings.HasPrefix(name, "tmp-"):
+			expired = file.ModTime().Before(now.Add(-diskPruneInterval))
+		case len(name) == hex.EncodedLen(sha256.Size):
+			expired = file.ModTime().Before(now)
+		}
+		if expired {
+			_ = os.Remove(filepath.Join(d.dir, file.Name()))
+		}
+	}
+
+	return nil
+}
+
+// path returns the name of the file holding the entry for a key.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
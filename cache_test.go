@@ -0,0 +1,199 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func cacheServer(requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		switch r.URL.Query().Get("t") {
+		case "get":
+			w.Header().Set("X-DNZB-Name", "The.Office.US.S02E22")
+			_, _ = w.Write([]byte(`<nzb/>`))
+		default:
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = w.Write([]byte(`<rss/>`))
+		}
+	}))
+}
+
+func TestCacheSearch(t *testing.T) {
+	requests := 0
+	server := cacheServer(&requests)
+	defer server.Close()
+
+	cache := NewMemoryCache(10)
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithCache(cache))
+	other := NewClient(WithUrl(server.URL), WithApikey("other-key"), WithCache(cache))
+
+	res, err := c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(res).IsEqualTo("<rss/>")
+
+	// The API key isn't part of the cache key.
+	res, err = other.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(res).IsEqualTo("<rss/>")
+	assert.With(t).That(requests).IsEqualTo(1)
+
+	_, err = c.Search(Query("something else"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(2)
+
+	_, err = c.SearchContext(BypassCache(context.Background()), Query("test"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(3)
+}
+
+func TestCacheGet(t *testing.T) {
+	requests := 0
+	server := cacheServer(&requests)
+	defer server.Close()
+
+	// Downloads aren't cached by default.
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithCache(NewMemoryCache(10)))
+	_, _ = c.GetNzbRaw("1a2b3c")
+	_, _ = c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(requests).IsEqualTo(2)
+
+	c = NewClient(WithUrl(server.URL), WithApikey("key"), WithCache(NewMemoryCache(10)),
+		WithCacheTTL("get", time.Hour))
+	raw, err := c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(err).IsNil()
+	raw, err = c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(string(raw.Data)).IsEqualTo("<nzb/>")
+	assert.With(t).That(raw.Name).IsEqualTo("The.Office.US.S02E22")
+	assert.With(t).That(requests).IsEqualTo(3)
+}
+
+func TestCacheDetails(t *testing.T) {
+	requests := 0
+	server := cacheServer(&requests)
+	defer server.Close()
+
+	// Details are looked up to refresh an item, so they always reach the server.
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithCache(NewMemoryCache(10)))
+	_, _ = c.GetDetails("1a2b3c")
+	_, _ = c.GetDetails("1a2b3c")
+	assert.With(t).That(requests).IsEqualTo(2)
+}
+
+func TestCacheKey(t *testing.T) {
+	u, _ := url.Parse("HTTP://Example.com/api?t=search&apikey=secret&q=test")
+	assert.With(t).That(cacheKey(u)).IsEqualTo("http://example.com/api?q=test&t=search")
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	expires := time.Now().Add(time.Hour)
+	cache.Set("a", &CacheEntry{Body: []byte("a"), Expires: expires})
+	cache.Set("b", &CacheEntry{Body: []byte("b"), Expires: expires})
+
+	// Reading "a" makes "b" the least recently used.
+	_, ok := cache.Get("a")
+	assert.With(t).That(ok).IsEqualTo(true)
+	cache.Set("c", &CacheEntry{Body: []byte("c"), Expires: expires})
+
+	_, ok = cache.Get("b")
+	assert.With(t).That(ok).IsEqualTo(false)
+	_, ok = cache.Get("c")
+	assert.With(t).That(ok).IsEqualTo(true)
+
+	cache.Set("d", &CacheEntry{Body: []byte("d"), Expires: time.Now().Add(-time.Second)})
+	_, ok = cache.Get("d")
+	assert.With(t).That(ok).IsEqualTo(false)
+
+	cache.Delete("c")
+	_, ok = cache.Get("c")
+	assert.With(t).That(ok).IsEqualTo(false)
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	assert.With(t).That(err).IsNil()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	cache.Set("a", &CacheEntry{Body: []byte("{}"), Header: header, Expires: time.Now().Add(time.Hour)})
+
+	entry, ok := cache.Get("a")
+	assert.With(t).That(ok).IsEqualTo(true)
+	assert.With(t).That(string(entry.Body)).IsEqualTo("{}")
+	assert.With(t).That(entry.Header.Get("Content-Type")).IsEqualTo("application/json")
+
+	cache.Set("b", &CacheEntry{Body: []byte("{}"), Expires: time.Now().Add(-time.Second)})
+	_, ok = cache.Get("b")
+	assert.With(t).That(ok).IsEqualTo(false)
+
+	cache.Delete("a")
+	_, ok = cache.Get("a")
+	assert.With(t).That(ok).IsEqualTo(false)
+}
+
+func TestDiskCachePrune(t *testing.T) {
+	dir := t.TempDir()
+	cache, _ := NewDiskCache(dir)
+	cache.Set("a", &CacheEntry{Body: []byte("a"), Expires: time.Now().Add(time.Hour)})
+	cache.Set("b", &CacheEntry{Body: []byte("b"), Expires: time.Now().Add(-time.Second)})
+	cache.Set("c", &CacheEntry{Body: []byte("c"), Expires: time.Now().Add(-time.Hour)})
+	assert.With(t).That(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600)).IsNil()
+	assert.With(t).That(os.Chtimes(filepath.Join(dir, "notes.txt"), time.Time{}, time.Unix(0, 0))).IsNil()
+
+	assert.With(t).That(cache.Prune()).IsNil()
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.With(t).That(len(files)).IsEqualTo(2)
+	_, ok := cache.Get("a")
+	assert.With(t).That(ok).IsEqualTo(true)
+}
+
+func TestCacheEntriesAreCopied(t *testing.T) {
+	requests := 0
+	server := cacheServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithCache(NewMemoryCache(10)),
+		WithCacheTTL("get", time.Hour))
+	b, err := c.GetTorrent("1a2b3c")
+	assert.With(t).That(err).IsNil()
+	b[0] = 'X'
+
+	b, err = c.GetTorrent("1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(string(b)).IsEqualTo("<nzb/>")
+
+	raw, _ := c.GetNzbRaw("1a2b3c")
+	raw.Header.Set("X-DNZB-Name", "changed")
+	raw.Data[0] = 'X'
+	raw, _ = c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(raw.Name).IsEqualTo("The.Office.US.S02E22")
+	assert.With(t).That(string(raw.Data)).IsEqualTo("<nzb/>")
+	assert.With(t).That(requests).IsEqualTo(1)
+}
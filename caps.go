@@ -0,0 +1,158 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"encoding/xml"
+	"golang.org/x/net/html/charset"
+	"strings"
+)
+
+// Caps describes the capabilities of a server, as returned by a "t=caps" request.
+type Caps struct {
+	XMLName      xml.Name         `xml:"caps" json:"-"`
+	Server       CapsServer       `xml:"server" json:"server"`
+	Limits       CapsLimits       `xml:"limits" json:"limits"`
+	Registration CapsRegistration `xml:"registration" json:"registration"`
+	Retention    CapsRetention    `xml:"retention" json:"retention"`
+	Searching    CapsSearching    `xml:"searching" json:"searching"`
+	Categories   []CapsCategory   `xml:"categories>category" json:"categories"`
+	Groups       []CapsGroup      `xml:"groups>group" json:"groups,omitempty"`
+	Genres       []CapsGenre      `xml:"genres>genre" json:"genres,omitempty"`
+}
+
+// CapsServer identifies the server software and the site running it.
+type CapsServer struct {
+	Version   string `xml:"version,attr" json:"version,omitempty"`
+	Title     string `xml:"title,attr" json:"title,omitempty"`
+	Strapline string `xml:"strapline,attr" json:"strapline,omitempty"`
+	Email     string `xml:"email,attr" json:"email,omitempty"`
+	URL       string `xml:"url,attr" json:"url,omitempty"`
+	Image     string `xml:"image,attr" json:"image,omitempty"`
+}
+
+// CapsLimits contains the maximum and default number of results returned by a search.
+type CapsLimits struct {
+	Max     int `xml:"max,attr" json:"max"`
+	Default int `xml:"default,attr" json:"default"`
+}
+
+// CapsRegistration describes whether new accounts can be registered through the API.
+type CapsRegistration struct {
+	Available string `xml:"available,attr" json:"available"`
+	Open      string `xml:"open,attr" json:"open"`
+}
+
+// CapsRetention contains the number of days of Usenet retention the server indexes.
+type CapsRetention struct {
+	Days int `xml:"days,attr" json:"days"`
+}
+
+// CapsSearching describes each type of search supported by the server.
+type CapsSearching struct {
+	Search      CapsSearch `xml:"search" json:"search"`
+	TvSearch    CapsSearch `xml:"tv-search" json:"tv-search"`
+	MovieSearch CapsSearch `xml:"movie-search" json:"movie-search"`
+	AudioSearch CapsSearch `xml:"audio-search" json:"audio-search"`
+	BookSearch  CapsSearch `xml:"book-search" json:"book-search"`
+}
+
+// CapsSearch describes whether a type of search is available and the parameters it supports.
+type CapsSearch struct {
+	Available       string `xml:"available,attr" json:"available"`
+	SupportedParams string `xml:"supportedParams,attr" json:"supportedParams"`
+}
+
+// CapsCategory is a top-level media category and its sub-categories.
+type CapsCategory struct {
+	Id          string       `xml:"id,attr" json:"id"`
+	Name        string       `xml:"name,attr" json:"name"`
+	Description string       `xml:"description,attr" json:"description,omitempty"`
+	Subcats     []CapsSubcat `xml:"subcat" json:"subcat,omitempty"`
+}
+
+// CapsSubcat is a media category nested within a CapsCategory.
+type CapsSubcat struct {
+	Id          string `xml:"id,attr" json:"id"`
+	Name        string `xml:"name,attr" json:"name"`
+	Description string `xml:"description,attr" json:"description,omitempty"`
+}
+
+// CapsGroup is a Usenet news group indexed by the server.
+type CapsGroup struct {
+	Id          string `xml:"id,attr" json:"id"`
+	Name        string `xml:"name,attr" json:"name"`
+	Description string `xml:"description,attr" json:"description,omitempty"`
+	LastUpdate  string `xml:"lastupdate,attr" json:"lastupdate,omitempty"`
+}
+
+// CapsGenre is a genre that can be used to restrict a search within a category.
+type CapsGenre struct {
+	Id         string `xml:"id,attr" json:"id"`
+	CategoryId string `xml:"categoryid,attr" json:"categoryid"`
+	Name       string `xml:"name,attr" json:"name"`
+}
+
+// IsAvailable reports whether registration is allowed through the API.
+func (r CapsRegistration) IsAvailable() bool {
+	return isYes(r.Available) && isYes(r.Open)
+}
+
+// IsAvailable reports whether the type of search can be used.
+func (s CapsSearch) IsAvailable() bool {
+	return isYes(s.Available)
+}
+
+// Params returns the names of the parameters supported by the type of search.
+func (s CapsSearch) Params() []string {
+	params := make([]string, 0)
+	for _, p := range strings.Split(s.SupportedParams, ",") {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// Supports reports whether the named parameter is supported by the type of search.
+func (s CapsSearch) Supports(name string) bool {
+	for _, p := range s.Params() {
+		if strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CapsFromXml decodes the XML content of a "t=caps" response to a Caps struct.
+func CapsFromXml(data []byte) (caps Caps, err error) {
+	reader := bytes.NewReader(data)
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = charset.NewReaderLabel
+	err = decoder.Decode(&caps)
+	return
+}
+
+// isYes reports whether an attribute has the value "yes", or one of its common variations.
+func isYes(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "true", "1":
+		return true
+	}
+	return false
+}
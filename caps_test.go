@@ -0,0 +1,51 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestCapsFromXml(t *testing.T) {
+	original, err := ioutil.ReadFile("testdata/caps.xml")
+	if err != nil {
+		t.Error(err)
+	}
+
+	caps, err := CapsFromXml(original)
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(caps.Server.Title).IsEqualTo("abNZB")
+	assert.With(t).That(caps.Limits.Max).IsEqualTo(100)
+	assert.With(t).That(caps.Limits.Default).IsEqualTo(50)
+	assert.With(t).That(caps.Retention.Days).IsEqualTo(3500)
+	assert.With(t).That(caps.Registration.IsAvailable()).IsEqualTo(false)
+
+	assert.With(t).That(caps.Searching.TvSearch.IsAvailable()).IsEqualTo(true)
+	assert.With(t).That(caps.Searching.TvSearch.Supports("tvdbid")).IsEqualTo(true)
+	assert.With(t).That(caps.Searching.MovieSearch.Supports("tvdbid")).IsEqualTo(false)
+	assert.With(t).That(caps.Searching.BookSearch.IsAvailable()).IsEqualTo(false)
+	assert.With(t).That(len(caps.Searching.AudioSearch.Params())).IsEqualTo(6)
+
+	assert.With(t).That(len(caps.Categories)).IsEqualTo(4)
+	assert.With(t).That(len(caps.Categories[2].Subcats)).IsEqualTo(3)
+	assert.With(t).That(caps.Categories[2].Subcats[2].Description).IsEqualTo("Japanese animation")
+	assert.With(t).That(len(caps.Groups)).IsEqualTo(2)
+	assert.With(t).That(len(caps.Genres)).IsEqualTo(2)
+	assert.With(t).That(caps.Genres[1].CategoryId).IsEqualTo("2000")
+}
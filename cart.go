@@ -0,0 +1,84 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+)
+
+// AddToCart adds a release, identified by its GUID, to the user's cart.
+func (c *Client) AddToCart(guid string) error {
+	return c.AddToCartContext(context.Background(), guid)
+}
+
+// AddToCartContext adds a release to the user's cart using the provided context.
+func (c *Client) AddToCartContext(ctx context.Context, guid string) error {
+	_, err := c.call(ctx, Apikey(c.key), nzbid(guid), Type("cartadd"))
+	return err
+}
+
+// GetCart returns the contents of the user's cart. The cart feed identifies the user by their ID, which is shown on
+// the indexer's RSS help page, and the API key.
+func (c *Client) GetCart(uid string, params ...Param) (*Newznab, error) {
+	return c.GetCartContext(context.Background(), uid, params...)
+}
+
+// GetCartContext returns the contents of the user's cart using the provided context.
+func (c *Client) GetCartContext(ctx context.Context, uid string, params ...Param) (*Newznab, error) {
+	return c.FeedContext(ctx, MyCart, uid, params...)
+}
+
+// RemoveFromCart removes a release, identified by its GUID, from the user's cart.
+func (c *Client) RemoveFromCart(guid string) error {
+	return c.RemoveFromCartContext(context.Background(), guid)
+}
+
+// RemoveFromCartContext removes a release from the user's cart using the provided context.
+func (c *Client) RemoveFromCartContext(ctx context.Context, guid string) error {
+	_, err := c.call(ctx, Apikey(c.key), nzbid(guid), Type("cartdel"))
+	return err
+}
+
+// AddToCart adds a release, identified by its GUID, to the user's cart.
+func AddToCart(url string, key string, guid string) error {
+	return newClient(url, key).AddToCart(guid)
+}
+
+// AddToCartContext adds a release to the user's cart using the provided context.
+func AddToCartContext(ctx context.Context, url string, key string, guid string) error {
+	return newClient(url, key).AddToCartContext(ctx, guid)
+}
+
+// GetCart returns the contents of the user's cart.
+func GetCart(url string, key string, uid string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).GetCart(uid, params...)
+}
+
+// GetCartContext returns the contents of the user's cart using the provided context.
+func GetCartContext(ctx context.Context, url string, key string, uid string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).GetCartContext(ctx, uid, params...)
+}
+
+// RemoveFromCart removes a release, identified by its GUID, from the user's cart.
+func RemoveFromCart(url string, key string, guid string) error {
+	return newClient(url, key).RemoveFromCart(guid)
+}
+
+// RemoveFromCartContext removes a release from the user's cart using the provided context.
+func RemoveFromCartContext(ctx context.Context, url string, key string, guid string) error {
+	return newClient(url, key).RemoveFromCartContext(ctx, guid)
+}
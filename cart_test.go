@@ -0,0 +1,50 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCart(t *testing.T) {
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		if r.URL.Path == "/rss" {
+			_, _ = w.Write([]byte(`<rss><channel><item><title>The.Office.US.S02E22.720p.WEB-DL</title></item></channel></rss>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<cartadd id="1a2b3c"/>`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL+"/api"), WithApikey("key"))
+	assert.With(t).That(c.AddToCart("1a2b3c")).IsNil()
+	assert.With(t).That(c.RemoveFromCart("1a2b3c")).IsNil()
+
+	cart, err := c.GetCart("38759")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(len(cart.Channel.Item)).IsEqualTo(1)
+
+	assert.With(t).That(len(requests)).IsEqualTo(3)
+	assert.With(t).That(requests[0]).IsEqualTo("/api?apikey=key&id=1a2b3c&t=cartadd")
+	assert.With(t).That(requests[1]).IsEqualTo("/api?apikey=key&id=1a2b3c&t=cartdel")
+	assert.With(t).That(requests[2]).IsEqualTo("/rss?i=38759&r=key&t=-1")
+}
@@ -17,56 +17,151 @@
 package newznab
 
 import (
+	"context"
 	"encoding/json"
-	"encoding/xml"
-	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
-var (
-	Execute = execute
-)
+// Client holds the settings used to communicate with a single Newznab server. A Client is safe for concurrent use.
+type Client struct {
+	httpClient *http.Client
+	timeout    time.Duration
+	userAgent  string
+	url        string
+	rssUrl     string
+	key        string
+
+	// Settings used to retry failed requests and limit how many are sent.
+	retryPolicy RetryPolicy
+	limiter     *limiter
+	limiterMu   sync.Mutex
+
+	// Settings used to cache responses.
+	cache     Cache
+	cacheTTLs map[string]time.Duration
+
+	// Settings used to validate search parameters.
+	validation        ValidationMode
+	validationHandler func(*ValidationError)
+	caps              *Caps
+	capsMu            sync.Mutex
+}
 
-// BookSearch performs a search restricted to e-books.
-func BookSearch(url string, key string, params ...Param) (string, error) {
-	p := append(params, extended(), Apikey(key), Type("book"))
-	u, err := EncodeUrl(url, p...)
-	if err != nil {
-		return "", err
+// Option configures a Client.
+type Option func(*Client)
+
+// NewClient returns a Client configured with the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Apply the timeout to a copy so that a shared http.Client is never modified.
+	if c.timeout > 0 {
+		hc := *c.httpClient
+		hc.Timeout = c.timeout
+		c.httpClient = &hc
+	}
+
+	return c
+}
+
+// WithApikey returns an Option that sets the key used to access the API.
+func WithApikey(key string) Option {
+	return func(c *Client) {
+		c.key = key
 	}
+}
+
+// WithHttpClient returns an Option that sets the http.Client used to make requests.
+func WithHttpClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithTimeout returns an Option that limits the time taken by each request.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithUrl returns an Option that sets the full URL of the Newznab API.
+func WithUrl(url string) Option {
+	return func(c *Client) {
+		c.url = url
+	}
+}
+
+// WithUserAgent returns an Option that sets the User-Agent header sent with each request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// BookSearch performs a search restricted to e-books.
+func (c *Client) BookSearch(params ...Param) (string, error) {
+	return c.BookSearchContext(context.Background(), params...)
+}
 
-	return Execute(u)
+// BookSearchContext performs a search restricted to e-books using the provided context.
+func (c *Client) BookSearchContext(ctx context.Context, params ...Param) (string, error) {
+	return c.search(ctx, "book", params...)
 }
 
 // GetCapabilities returns the capabilities of the server.
-func GetCapabilities(url string) (string, error) {
-	// Build the URL to request from.
-	u, err := EncodeUrl(url, Type("caps"))
+func (c *Client) GetCapabilities() (string, error) {
+	return c.GetCapabilitiesContext(context.Background())
+}
 
+// GetCapabilitiesContext returns the capabilities of the server using the provided context.
+func (c *Client) GetCapabilitiesContext(ctx context.Context) (string, error) {
+	res, err := c.call(ctx, Type("caps"))
 	if err != nil {
 		return "", err
 	}
 
-	return Execute(u)
+	return string(res.body), nil
 }
 
-func GetNzb(url string, key string, id string) (string, error) {
-	u, err := EncodeUrl(url, Apikey(key), nzbid(id), Type("get"))
+// GetCapabilitiesParsed returns the decoded capabilities of the server.
+func (c *Client) GetCapabilitiesParsed() (*Caps, error) {
+	return c.GetCapabilitiesParsedContext(context.Background())
+}
+
+// GetCapabilitiesParsedContext returns the decoded capabilities of the server using the provided context.
+func (c *Client) GetCapabilitiesParsedContext(ctx context.Context) (*Caps, error) {
+	res, err := c.call(ctx, Type("caps"))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Retrieve the NZB file.
-	body, err := Execute(u)
+	caps, err := CapsFromXml(res.body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return &caps, nil
+}
+
+// GetNzb retrieves an NZB file and returns it in JSON format.
+func (c *Client) GetNzb(id string) (string, error) {
+	return c.GetNzbContext(context.Background(), id)
+}
 
-	// Unmarshal the NZB.
-	var nzb Nzb
-	err = xml.Unmarshal([]byte(body), &nzb)
+// GetNzbContext retrieves an NZB file using the provided context and returns it in JSON format.
+func (c *Client) GetNzbContext(ctx context.Context, id string) (string, error) {
+	nzb, err := c.GetNzbParsedContext(ctx, id)
 	if err != nil {
 		return "", err
 	}
@@ -81,87 +176,381 @@ func GetNzb(url string, key string, id string) (string, error) {
 }
 
 // MovieSearch performs a search restricted to movies.
-func MovieSearch(url string, key string, params ...Param) (string, error) {
-	p := append(params, extended(), Apikey(key), Type("movie"))
-	u, err := EncodeUrl(url, p...)
-	if err != nil {
-		return "", err
-	}
+func (c *Client) MovieSearch(params ...Param) (string, error) {
+	return c.MovieSearchContext(context.Background(), params...)
+}
 
-	return Execute(u)
+// MovieSearchContext performs a search restricted to movies using the provided context.
+func (c *Client) MovieSearchContext(ctx context.Context, params ...Param) (string, error) {
+	return c.search(ctx, "movie", params...)
 }
 
 // MusicSearch performs a search restricted to music.
-func MusicSearch(url string, key string, params ...Param) (string, error) {
-	p := append(params, extended(), Apikey(key), Type("music"))
-	u, err := EncodeUrl(url, p...)
+func (c *Client) MusicSearch(params ...Param) (string, error) {
+	return c.MusicSearchContext(context.Background(), params...)
+}
+
+// MusicSearchContext performs a search restricted to music using the provided context.
+func (c *Client) MusicSearchContext(ctx context.Context, params ...Param) (string, error) {
+	return c.search(ctx, "music", params...)
+}
+
+// Search performs a general search which can include any of media.
+func (c *Client) Search(params ...Param) (string, error) {
+	return c.SearchContext(context.Background(), params...)
+}
+
+// SearchContext performs a general search using the provided context.
+func (c *Client) SearchContext(ctx context.Context, params ...Param) (string, error) {
+	return c.search(ctx, "search", params...)
+}
+
+// TvSearch performs a search restricted to TV shows.
+func (c *Client) TvSearch(params ...Param) (string, error) {
+	return c.TvSearchContext(context.Background(), params...)
+}
+
+// TvSearchContext performs a search restricted to TV shows using the provided context.
+func (c *Client) TvSearchContext(ctx context.Context, params ...Param) (string, error) {
+	return c.search(ctx, "tvsearch", params...)
+}
+
+// BookSearchParsed performs a search restricted to e-books and returns the decoded results.
+func (c *Client) BookSearchParsed(params ...Param) (*Newznab, error) {
+	return c.BookSearchParsedContext(context.Background(), params...)
+}
+
+// BookSearchParsedContext performs a search restricted to e-books using the provided context and returns the decoded results.
+func (c *Client) BookSearchParsedContext(ctx context.Context, params ...Param) (*Newznab, error) {
+	return c.searchParsed(ctx, "book", params...)
+}
+
+// MovieSearchParsed performs a search restricted to movies and returns the decoded results.
+func (c *Client) MovieSearchParsed(params ...Param) (*Newznab, error) {
+	return c.MovieSearchParsedContext(context.Background(), params...)
+}
+
+// MovieSearchParsedContext performs a search restricted to movies using the provided context and returns the decoded results.
+func (c *Client) MovieSearchParsedContext(ctx context.Context, params ...Param) (*Newznab, error) {
+	return c.searchParsed(ctx, "movie", params...)
+}
+
+// MusicSearchParsed performs a search restricted to music and returns the decoded results.
+func (c *Client) MusicSearchParsed(params ...Param) (*Newznab, error) {
+	return c.MusicSearchParsedContext(context.Background(), params...)
+}
+
+// MusicSearchParsedContext performs a search restricted to music using the provided context and returns the decoded results.
+func (c *Client) MusicSearchParsedContext(ctx context.Context, params ...Param) (*Newznab, error) {
+	return c.searchParsed(ctx, "music", params...)
+}
+
+// SearchParsed performs a general search and returns the decoded results.
+func (c *Client) SearchParsed(params ...Param) (*Newznab, error) {
+	return c.SearchParsedContext(context.Background(), params...)
+}
+
+// SearchParsedContext performs a general search using the provided context and returns the decoded results.
+func (c *Client) SearchParsedContext(ctx context.Context, params ...Param) (*Newznab, error) {
+	return c.searchParsed(ctx, "search", params...)
+}
+
+// TvSearchParsed performs a search restricted to TV shows and returns the decoded results.
+func (c *Client) TvSearchParsed(params ...Param) (*Newznab, error) {
+	return c.TvSearchParsedContext(context.Background(), params...)
+}
+
+// TvSearchParsedContext performs a search restricted to TV shows using the provided context and returns the decoded results.
+func (c *Client) TvSearchParsedContext(ctx context.Context, params ...Param) (*Newznab, error) {
+	return c.searchParsed(ctx, "tvsearch", params...)
+}
+
+// search adds the parameters common to every type of search and runs the query.
+func (c *Client) search(ctx context.Context, t string, params ...Param) (string, error) {
+	p, err := c.searchParams(ctx, t, params)
 	if err != nil {
 		return "", err
 	}
 
-	return Execute(u)
+	res, err := c.call(ctx, p...)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res.body), nil
 }
 
-// Search performs a general search which can include any of media.
-func Search(url string, key string, params ...Param) (string, error) {
-	p := append(params, extended(), Apikey(key), Type("search"))
-	u, err := EncodeUrl(url, p...)
+// searchParsed runs the query and decodes the results according to the response's content type.
+func (c *Client) searchParsed(ctx context.Context, t string, params ...Param) (*Newznab, error) {
+	p, err := c.searchParams(ctx, t, params)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	res, err := c.call(ctx, p...)
+	if err != nil {
+		return nil, err
 	}
 
-	return Execute(u)
+	return decodeNewznab(res.header.Get("Content-Type"), res.body)
 }
 
-// TvSearch performs a search restricted to TV shows.
-func TvSearch(url string, key string, params ...Param) (string, error) {
-	p := append(params, extended(), Apikey(key), Type("tvsearch"))
-	u, err := EncodeUrl(url, p...)
+// searchParams validates the parameters for a search of type "t" and adds those common to every search.
+func (c *Client) searchParams(ctx context.Context, t string, params []Param) ([]Param, error) {
+	valid, err := c.validate(ctx, t, params)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return Execute(u)
+	p := make([]Param, 0, len(valid)+3)
+	p = append(p, valid...)
+	return append(p, extended(), Apikey(c.key), Type(t)), nil
 }
 
-// EncodeUrl returns a URL with a properly encoded query string.
-func EncodeUrl(base string, params ...Param) (*url.URL, error) {
-	// Parse the base URL.
-	u, err := url.Parse(base)
+// call encodes the parameters into the API URL and performs a GET operation.
+func (c *Client) call(ctx context.Context, params ...Param) (*response, error) {
+	u, err := EncodeUrl(c.url, params...)
 	if err != nil {
 		return nil, err
 	}
 
-	q := url.Values{}
-	for _, param := range params {
-		q.Add(param.Name, param.Value)
+	return c.fetch(ctx, u)
+}
+
+// fetch performs a GET operation and returns the response body and headers. Cached responses are returned without
+// contacting the server, and transient failures are retried according to the client's RetryPolicy.
+func (c *Client) fetch(ctx context.Context, u *url.URL) (*response, error) {
+	t := u.Query().Get("t")
+	if entry, ok := c.cached(ctx, u, t); ok {
+		return &response{body: entry.Body, header: entry.Header}, nil
 	}
 
-	u.RawQuery = q.Encode()
-	return u, nil
+	var res *response
+	err := c.retry(ctx, u, func() (http.Header, error) {
+		var err error
+		res, err = c.fetchOnce(ctx, u)
+		if err != nil && res != nil {
+			return res.header, err
+		}
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(u, t, res.body, res.header)
+	return res, nil
 }
 
-// Execute accepts the constructed URL and performs a GET operation.
-func execute(u *url.URL) (string, error) {
-	// Run the request
-	res, err := http.Get(u.String())
+// fetchOnce performs a single GET operation. The response is returned with any error once the headers have been
+// received.
+func (c *Client) fetchOnce(ctx context.Context, u *url.URL) (*response, error) {
+	res, err := c.open(ctx, u, u.Query().Get("t") == "get")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer res.Body.Close()
 
+	// Read the response body
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return &response{header: res.Header}, err
+	}
+
+	return &response{body: body, header: res.Header}, checkResponse(res, body)
+}
+
+// open performs a GET operation and returns the response without reading the body. The caller must close it. The
+// request waits for the client's rate limit, and counts as a grab when grab is true.
+func (c *Client) open(ctx context.Context, u *url.URL, grab bool) (*http.Response, error) {
+	if err := c.wait(ctx, grab); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.userAgent) > 0 {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// Run the request
+	return c.httpClient.Do(req)
+}
+
+// checkResponse returns an error if the body is a Newznab error document or the status isn't OK. The body may be just
+// the start of the response.
+func checkResponse(res *http.Response, body []byte) error {
+	// Servers report errors in the body, usually with a 200 status.
+	if err := parseError(body); err != nil {
+		return err
+	}
+
 	// Bail out now if the status isn't OK.
 	if res.StatusCode != http.StatusOK {
-		return "", errors.New(res.Status)
+		return &StatusError{StatusCode: res.StatusCode, Status: res.Status}
 	}
 
-	// Return the response body
-	body, err := ioutil.ReadAll(res.Body)
+	return nil
+}
+
+// response holds the parts of an HTTP response used by the library.
+type response struct {
+	body   []byte
+	header http.Header
+}
+
+// BookSearch performs a search restricted to e-books.
+func BookSearch(url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).BookSearch(params...)
+}
+
+// BookSearchContext performs a search restricted to e-books using the provided context.
+func BookSearchContext(ctx context.Context, url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).BookSearchContext(ctx, params...)
+}
+
+// GetCapabilities returns the capabilities of the server.
+func GetCapabilities(url string) (string, error) {
+	return newClient(url, "").GetCapabilities()
+}
+
+// GetCapabilitiesContext returns the capabilities of the server using the provided context.
+func GetCapabilitiesContext(ctx context.Context, url string) (string, error) {
+	return newClient(url, "").GetCapabilitiesContext(ctx)
+}
+
+// GetCapabilitiesParsed returns the decoded capabilities of the server.
+func GetCapabilitiesParsed(url string) (*Caps, error) {
+	return newClient(url, "").GetCapabilitiesParsed()
+}
+
+// GetCapabilitiesParsedContext returns the decoded capabilities of the server using the provided context.
+func GetCapabilitiesParsedContext(ctx context.Context, url string) (*Caps, error) {
+	return newClient(url, "").GetCapabilitiesParsedContext(ctx)
+}
+
+// GetNzb retrieves an NZB file and returns it in JSON format.
+func GetNzb(url string, key string, id string) (string, error) {
+	return newClient(url, key).GetNzb(id)
+}
+
+// GetNzbContext retrieves an NZB file using the provided context and returns it in JSON format.
+func GetNzbContext(ctx context.Context, url string, key string, id string) (string, error) {
+	return newClient(url, key).GetNzbContext(ctx, id)
+}
+
+// MovieSearch performs a search restricted to movies.
+func MovieSearch(url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).MovieSearch(params...)
+}
+
+// MovieSearchContext performs a search restricted to movies using the provided context.
+func MovieSearchContext(ctx context.Context, url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).MovieSearchContext(ctx, params...)
+}
+
+// MusicSearch performs a search restricted to music.
+func MusicSearch(url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).MusicSearch(params...)
+}
+
+// MusicSearchContext performs a search restricted to music using the provided context.
+func MusicSearchContext(ctx context.Context, url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).MusicSearchContext(ctx, params...)
+}
+
+// Search performs a general search which can include any of media.
+func Search(url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).Search(params...)
+}
+
+// SearchContext performs a general search using the provided context.
+func SearchContext(ctx context.Context, url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).SearchContext(ctx, params...)
+}
+
+// TvSearch performs a search restricted to TV shows.
+func TvSearch(url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).TvSearch(params...)
+}
+
+// TvSearchContext performs a search restricted to TV shows using the provided context.
+func TvSearchContext(ctx context.Context, url string, key string, params ...Param) (string, error) {
+	return newClient(url, key).TvSearchContext(ctx, params...)
+}
+
+// BookSearchParsed performs a search restricted to e-books and returns the decoded results.
+func BookSearchParsed(url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).BookSearchParsed(params...)
+}
+
+// BookSearchParsedContext performs a search restricted to e-books using the provided context and returns the decoded results.
+func BookSearchParsedContext(ctx context.Context, url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).BookSearchParsedContext(ctx, params...)
+}
+
+// MovieSearchParsed performs a search restricted to movies and returns the decoded results.
+func MovieSearchParsed(url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).MovieSearchParsed(params...)
+}
+
+// MovieSearchParsedContext performs a search restricted to movies using the provided context and returns the decoded results.
+func MovieSearchParsedContext(ctx context.Context, url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).MovieSearchParsedContext(ctx, params...)
+}
+
+// MusicSearchParsed performs a search restricted to music and returns the decoded results.
+func MusicSearchParsed(url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).MusicSearchParsed(params...)
+}
+
+// MusicSearchParsedContext performs a search restricted to music using the provided context and returns the decoded results.
+func MusicSearchParsedContext(ctx context.Context, url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).MusicSearchParsedContext(ctx, params...)
+}
+
+// SearchParsed performs a general search and returns the decoded results.
+func SearchParsed(url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).SearchParsed(params...)
+}
+
+// SearchParsedContext performs a general search using the provided context and returns the decoded results.
+func SearchParsedContext(ctx context.Context, url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).SearchParsedContext(ctx, params...)
+}
+
+// TvSearchParsed performs a search restricted to TV shows and returns the decoded results.
+func TvSearchParsed(url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).TvSearchParsed(params...)
+}
+
+// TvSearchParsedContext performs a search restricted to TV shows using the provided context and returns the decoded results.
+func TvSearchParsedContext(ctx context.Context, url string, key string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).TvSearchParsedContext(ctx, params...)
+}
+
+// EncodeUrl returns a URL with a properly encoded query string.
+func EncodeUrl(base string, params ...Param) (*url.URL, error) {
+	// Parse the base URL.
+	u, err := url.Parse(base)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return string(body), nil
+	q := url.Values{}
+	for _, param := range params {
+		q.Add(param.Name, param.Value)
+	}
+
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+// newClient returns a Client for the stateless package-level functions.
+func newClient(url string, key string) *Client {
+	return NewClient(WithUrl(url), WithApikey(key))
 }
 
 // extended returns a Param that directs the service to produce all extended attributes.
@@ -0,0 +1,86 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"errors"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientSearch(t *testing.T) {
+	var query, agent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		agent = r.UserAgent()
+		_, _ = w.Write([]byte("<rss/>"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL+"/api"), WithApikey("key"), WithUserAgent("test/1.0"))
+	res, err := c.TvSearch(Query("The Office"))
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(res).IsEqualTo("<rss/>")
+	assert.With(t).That(agent).IsEqualTo("test/1.0")
+	assert.With(t).That(query).IsEqualTo("apikey=key&extended=1&q=The+Office&t=tvsearch")
+}
+
+func TestClientStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := Search(server.URL, "key", Query("test"))
+	assert.With(t).That(err).IsNotNil()
+}
+
+func TestClientContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<rss/>"))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SearchContext(ctx, server.URL, "key", Query("test"))
+	assert.With(t).That(errors.Is(err, context.Canceled)).IsEqualTo(true)
+}
+
+func TestClientSearchParsed(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/search-results.xml")
+	if err != nil {
+		t.Error(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	res, err := MovieSearchParsed(server.URL, "key", Query("Sword Art Online"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(12)
+	assert.With(t).That(res.Channel.Response.Total).IsEqualTo("12")
+}
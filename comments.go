@@ -0,0 +1,143 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"golang.org/x/net/html/charset"
+	"strings"
+	"time"
+)
+
+// Comment is a comment posted about a release.
+type Comment struct {
+	Author string
+	Date   time.Time
+	Text   string
+}
+
+// commentsFeed is the RSS feed returned by a "t=comments" request. Servers identify the author in different elements,
+// so each of them is decoded.
+type commentsFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Item    []struct {
+		Title       string `xml:"title"`
+		Author      string `xml:"author"`
+		Creator     string `xml:"creator"`
+		PubDate     string `xml:"pubDate"`
+		Description string `xml:"description"`
+	} `xml:"channel>item"`
+}
+
+// commentAdded is the response to a "t=commentadd" request.
+type commentAdded struct {
+	Id string `xml:"id,attr"`
+}
+
+// AddComment posts a comment about a release, identified by its GUID, and returns the ID of the new comment if the
+// server provides one.
+func (c *Client) AddComment(guid string, text string) (string, error) {
+	return c.AddCommentContext(context.Background(), guid, text)
+}
+
+// AddCommentContext posts a comment about a release using the provided context.
+func (c *Client) AddCommentContext(ctx context.Context, guid string, text string) (string, error) {
+	res, err := c.call(ctx, Apikey(c.key), nzbid(guid), commentText(text), Type("commentadd"))
+	if err != nil {
+		return "", err
+	}
+
+	// Not every server describes the new comment, so a response that can't be decoded still means success.
+	var added commentAdded
+	_ = xmlDecode(res.body, &added)
+	return added.Id, nil
+}
+
+// GetComments returns the comments posted about a release, identified by its GUID.
+func (c *Client) GetComments(guid string) ([]Comment, error) {
+	return c.GetCommentsContext(context.Background(), guid)
+}
+
+// GetCommentsContext returns the comments posted about a release using the provided context.
+func (c *Client) GetCommentsContext(ctx context.Context, guid string) ([]Comment, error) {
+	res, err := c.call(ctx, Apikey(c.key), nzbid(guid), Type("comments"))
+	if err != nil {
+		return nil, err
+	}
+
+	var feed commentsFeed
+	if err = xmlDecode(res.body, &feed); err != nil {
+		return nil, err
+	}
+
+	comments := make([]Comment, len(feed.Item))
+	for i, item := range feed.Item {
+		author := item.Author
+		if len(author) == 0 {
+			author = item.Creator
+		}
+		if len(author) == 0 {
+			author = item.Title
+		}
+
+		date, _ := parseDate(item.PubDate)
+		comments[i] = Comment{
+			Author: strings.TrimSpace(author),
+			Date:   date,
+			Text:   item.Description,
+		}
+	}
+
+	return comments, nil
+}
+
+// AddComment posts a comment about a release, identified by its GUID.
+func AddComment(url string, key string, guid string, text string) (string, error) {
+	return newClient(url, key).AddComment(guid, text)
+}
+
+// AddCommentContext posts a comment about a release using the provided context.
+func AddCommentContext(ctx context.Context, url string, key string, guid string, text string) (string, error) {
+	return newClient(url, key).AddCommentContext(ctx, guid, text)
+}
+
+// GetComments returns the comments posted about a release, identified by its GUID.
+func GetComments(url string, key string, guid string) ([]Comment, error) {
+	return newClient(url, key).GetComments(guid)
+}
+
+// GetCommentsContext returns the comments posted about a release using the provided context.
+func GetCommentsContext(ctx context.Context, url string, key string, guid string) ([]Comment, error) {
+	return newClient(url, key).GetCommentsContext(ctx, guid)
+}
+
+// commentText returns a Param that contains the text of a new comment.
+func commentText(text string) Param {
+	return Param{
+		Name:  "text",
+		Value: text,
+	}
+}
+
+// xmlDecode decodes XML content that may use a character set other than UTF-8.
+func xmlDecode(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder.Decode(v)
+}
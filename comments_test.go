@@ -0,0 +1,60 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetComments(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<item><title>alice</title><pubDate>Wed, 18 Nov 2020 03:42:40 +0000</pubDate><description>Missing par2 files</description></item>
+<item><title>Re: broken</title><dc:creator>bob</dc:creator><pubDate>Thu, 19 Nov 2020 03:42:40 +0000</pubDate><description>Works for me</description></item>
+</channel></rss>`))
+	}))
+	defer server.Close()
+
+	comments, err := GetComments(server.URL, "key", "1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(query).IsEqualTo("apikey=key&id=1a2b3c&t=comments")
+	assert.With(t).That(len(comments)).IsEqualTo(2)
+	assert.With(t).That(comments[0].Author).IsEqualTo("alice")
+	assert.With(t).That(comments[0].Text).IsEqualTo("Missing par2 files")
+	assert.With(t).That(comments[0].Date.Day()).IsEqualTo(18)
+	assert.With(t).That(comments[1].Author).IsEqualTo("bob")
+}
+
+func TestAddComment(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<commentadd id="17"/>`))
+	}))
+	defer server.Close()
+
+	id, err := AddComment(server.URL, "key", "1a2b3c", "Missing par2 files")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(id).IsEqualTo("17")
+	assert.With(t).That(query).IsEqualTo("apikey=key&id=1a2b3c&t=commentadd&text=Missing+par2+files")
+}
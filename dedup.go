@@ -0,0 +1,209 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Preference chooses which item of a DuplicateGroup is the preferred one.
+type Preference int
+
+const (
+	// PreferPriority chooses the item from the indexer with the highest Priority.
+	PreferPriority Preference = iota
+
+	// PreferGrabs chooses the item that has been downloaded the most.
+	PreferGrabs
+
+	// PreferNewest chooses the item that was most recently published by its indexer.
+	PreferNewest
+)
+
+// DuplicateGroup contains the copies of one release found on one or more indexers.
+type DuplicateGroup struct {
+	// Preferred is the item to download first.
+	Preferred FederatedItem
+
+	// Alternates are the other copies of the release, in order of preference, to fall back to if the preferred item
+	// fails.
+	Alternates []FederatedItem
+}
+
+// DedupOption configures how duplicates are detected.
+type DedupOption func(*deduper)
+
+// deduper holds the settings used to group duplicates.
+type deduper struct {
+	sizeTolerance float64
+	dateTolerance time.Duration
+	prefer        Preference
+	priorities    map[string]int
+}
+
+// DateTolerance returns a DedupOption that sets how far apart the Usenet post dates of two items can be for them to
+// be considered the same release. The default is one hour.
+func DateTolerance(tolerance time.Duration) DedupOption {
+	return func(d *deduper) {
+		d.dateTolerance = tolerance
+	}
+}
+
+// PreferBy returns a DedupOption that sets how the preferred item of each group is chosen. The default is
+// PreferPriority.
+func PreferBy(p Preference) DedupOption {
+	return func(d *deduper) {
+		d.prefer = p
+	}
+}
+
+// SizeTolerance returns a DedupOption that sets the fraction two sizes can differ by for the items to be considered the
+// same release. The default is 0.01, or one percent.
+func SizeTolerance(f float64) DedupOption {
+	return func(d *deduper) {
+		d.sizeTolerance = f
+	}
+}
+
+// Deduplicate groups the items that are copies of the same release, using the Priority of the Federation's indexers
+// to choose between them.
+func (f *Federation) Deduplicate(items []FederatedItem, opts ...DedupOption) []DuplicateGroup {
+	priorities := make(map[string]int, len(f.indexers))
+	for _, indexer := range f.indexers {
+		priorities[indexer.Name] = indexer.Priority
+	}
+
+	return deduplicate(items, priorities, opts)
+}
+
+// Deduplicate groups the items that are copies of the same release. Items are duplicates when their normalized titles
+// are equal, and their sizes, posters and Usenet dates match wherever both items report them. The groups are returned
+// in the order their first item appears.
+func Deduplicate(items []FederatedItem, opts ...DedupOption) []DuplicateGroup {
+	return deduplicate(items, nil, opts)
+}
+
+func deduplicate(items []FederatedItem, priorities map[string]int, opts []DedupOption) []DuplicateGroup {
+	d := &deduper{
+		sizeTolerance: 0.01,
+		dateTolerance: time.Hour,
+		prefer:        PreferPriority,
+		priorities:    priorities,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	// Group the items by title first, then compare within each title.
+	groups := make([][]FederatedItem, 0)
+	byTitle := make(map[string][]int)
+	for _, item := range items {
+		title := normalizeTitle(item.Title)
+
+		found := false
+		for _, g := range byTitle[title] {
+			if d.matches(groups[g][0], item) {
+				groups[g] = append(groups[g], item)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			byTitle[title] = append(byTitle[title], len(groups))
+			groups = append(groups, []FederatedItem{item})
+		}
+	}
+
+	res := make([]DuplicateGroup, len(groups))
+	for i, g := range groups {
+		sort.SliceStable(g, func(a, b int) bool {
+			return d.less(g[a], g[b])
+		})
+		res[i] = DuplicateGroup{
+			Preferred:  g[0],
+			Alternates: g[1:],
+		}
+	}
+
+	return res
+}
+
+// matches reports whether two items with the same normalized title are the same release.
+func (d *deduper) matches(a FederatedItem, b FederatedItem) bool {
+	if sa, sb := a.Size(), b.Size(); sa > 0 && sb > 0 {
+		diff := sa - sb
+		if diff < 0 {
+			diff = -diff
+		}
+		max := sa
+		if sb > max {
+			max = sb
+		}
+		if float64(diff) > d.sizeTolerance*float64(max) {
+			return false
+		}
+	}
+
+	if pa, pb := a.AttrValue("poster"), b.AttrValue("poster"); len(pa) > 0 && len(pb) > 0 {
+		if !strings.EqualFold(pa, pb) {
+			return false
+		}
+	}
+
+	if ta, tb := a.UsenetDate(), b.UsenetDate(); !ta.IsZero() && !tb.IsZero() {
+		diff := ta.Sub(tb)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > d.dateTolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// less reports whether item a is preferred over item b.
+func (d *deduper) less(a FederatedItem, b FederatedItem) bool {
+	switch d.prefer {
+	case PreferGrabs:
+		ga, gb := a.Grabs(), b.Grabs()
+		if ga != gb {
+			return ga > gb
+		}
+	case PreferNewest:
+		if ta, tb := a.Published(), b.Published(); !ta.Equal(tb) {
+			return ta.After(tb)
+		}
+	}
+
+	// Fall back to the indexer's priority.
+	return d.priorities[a.Indexer] > d.priorities[b.Indexer]
+}
+
+// normalizeTitle lower-cases a title and reduces punctuation and runs of spaces to a single space, so that "The.Movie"
+// and "The Movie" are equal.
+func normalizeTitle(title string) string {
+	fields := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"fmt"
+	"github.com/MediaExchange/assert"
+	"testing"
+)
+
+func testItem(indexer string, title string, size string, grabs string, date string) FederatedItem {
+	data := fmt.Sprintf(`<rss><channel><item><title>%s</title>
+<newznab:attr name="size" value="%s"/>
+<newznab:attr name="grabs" value="%s"/>
+<newznab:attr name="usenetdate" value="%s"/>
+<newznab:attr name="poster" value="poster@example.com"/>
+</item></channel></rss>`, title, size, grabs, date)
+
+	newznab, err := NewznabFromXml([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return FederatedItem{Item: newznab.Channel.Item[0], Indexer: indexer}
+}
+
+func TestDeduplicate(t *testing.T) {
+	items := []FederatedItem{
+		testItem("a", "The.Office.S02E22.720p", "1000000", "5", "Tue, 17 Nov 2020 23:41:48 +0000"),
+		testItem("b", "The Office S02E22 720p", "1004000", "50", "Tue, 17 Nov 2020 23:45:00 +0000"),
+		testItem("c", "the office s02e22 720p", "2000000", "1", "Tue, 17 Nov 2020 23:41:48 +0000"),
+		testItem("c", "The Office S02E23 720p", "1000000", "1", "Tue, 17 Nov 2020 23:41:48 +0000"),
+		testItem("c", "The.Office.S02E22.720p", "1000000", "1", "Tue, 24 Nov 2020 23:41:48 +0000"),
+	}
+
+	f := NewFederation(Indexer{Name: "a", Priority: 1}, Indexer{Name: "b", Priority: 2}, Indexer{Name: "c"})
+
+	groups := f.Deduplicate(items)
+	assert.With(t).That(len(groups)).IsEqualTo(4)
+	assert.With(t).That(groups[0].Preferred.Indexer).IsEqualTo("b")
+	assert.With(t).That(len(groups[0].Alternates)).IsEqualTo(1)
+	assert.With(t).That(groups[0].Alternates[0].Indexer).IsEqualTo("a")
+
+	groups = Deduplicate(items, PreferBy(PreferGrabs), SizeTolerance(0))
+	assert.With(t).That(len(groups)).IsEqualTo(5)
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+)
+
+// GetDetails returns the full metadata of a single release, identified by its GUID.
+func (c *Client) GetDetails(guid string) (*Item, error) {
+	return c.GetDetailsContext(context.Background(), guid)
+}
+
+// GetDetailsContext returns the full metadata of a single release using the provided context.
+func (c *Client) GetDetailsContext(ctx context.Context, guid string) (*Item, error) {
+	res, err := c.call(ctx, Apikey(c.key), nzbid(guid), Type("details"))
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := decodeNewznab(res.header.Get("Content-Type"), res.body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The release is returned as the only item of a feed.
+	if len(feed.Channel.Item) == 0 {
+		return nil, ErrNoSuchItem
+	}
+	return &feed.Channel.Item[0], nil
+}
+
+// GetDetails returns the full metadata of a single release, identified by its GUID.
+func GetDetails(url string, key string, guid string) (*Item, error) {
+	return newClient(url, key).GetDetails(guid)
+}
+
+// GetDetailsContext returns the full metadata of a single release using the provided context.
+func GetDetailsContext(ctx context.Context, url string, key string, guid string) (*Item, error) {
+	return newClient(url, key).GetDetailsContext(ctx, guid)
+}
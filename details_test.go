@@ -0,0 +1,56 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"errors"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDetails(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<rss><channel><item>
+<title>The.Office.US.S02E22.720p.WEB-DL</title>
+<guid isPermaLink="true">https://example.com/details/1a2b3c</guid>
+<newznab:attr name="grabs" value="42"/>
+<newznab:attr name="password" value="1"/>
+</item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	item, err := GetDetails(server.URL, "key", "1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(query).IsEqualTo("apikey=key&id=1a2b3c&t=details")
+	assert.With(t).That(item.Id()).IsEqualTo("1a2b3c")
+	assert.With(t).That(item.Grabs()).IsEqualTo(42)
+	assert.With(t).That(int(item.Password())).IsEqualTo(int(PasswordRar))
+}
+
+func TestGetDetailsNoSuchItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<rss><channel></channel></rss>`))
+	}))
+	defer server.Close()
+
+	_, err := GetDetails(server.URL, "key", "missing")
+	assert.With(t).That(errors.Is(err, ErrNoSuchItem)).IsEqualTo(true)
+}
@@ -0,0 +1,287 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NzbInfo describes a downloaded NZB file using the headers sent with it. Servers that follow the DNZB convention
+// send X-DNZB-* headers with the release's name, category and password.
+type NzbInfo struct {
+	// Filename is taken from the Content-Disposition header.
+	Filename string
+
+	// Name, Category and Password are taken from the X-DNZB-Name, X-DNZB-Category and X-DNZB-Password headers.
+	Name     string
+	Category string
+	Password string
+
+	// Header contains every X-DNZB-* header, including those without a field above.
+	Header http.Header
+}
+
+// RawNzb is an NZB file exactly as it was sent by the server, including its DOCTYPE.
+type RawNzb struct {
+	NzbInfo
+	Data []byte
+}
+
+// peekSize is the amount of a streamed response that is inspected for a Newznab error document.
+const peekSize = 4096
+
+// DownloadNzb retrieves the NZB file of a search result from its enclosure URL and returns its original bytes. Unlike
+// GetNzbRaw it works with servers that have disabled t=get or serve files from another path.
+func (c *Client) DownloadNzb(item Item) (*RawNzb, error) {
+	return c.DownloadNzbContext(context.Background(), item)
+}
+
+// DownloadNzbContext retrieves the NZB file of a search result from its enclosure URL using the provided context.
+func (c *Client) DownloadNzbContext(ctx context.Context, item Item) (*RawNzb, error) {
+	u, err := c.itemUrl(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	info, err := c.download(ctx, u, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RawNzb{NzbInfo: *info, Data: buf.Bytes()}, nil
+}
+
+// DownloadNzbTo retrieves the NZB file of a search result from its enclosure URL and writes it to w as it is
+// received.
+func (c *Client) DownloadNzbTo(item Item, w io.Writer) (*NzbInfo, error) {
+	return c.DownloadNzbToContext(context.Background(), item, w)
+}
+
+// DownloadNzbToContext retrieves the NZB file of a search result from its enclosure URL using the provided context
+// and writes it to w as it is received.
+func (c *Client) DownloadNzbToContext(ctx context.Context, item Item, w io.Writer) (*NzbInfo, error) {
+	u, err := c.itemUrl(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.download(ctx, u, w)
+}
+
+// itemUrl returns the URL of an item's NZB file. Relative URLs are resolved against the client's URL, and the key is
+// added when the URL points at the client's server but doesn't already carry one.
+func (c *Client) itemUrl(item Item) (*url.URL, error) {
+	ref := strings.TrimSpace(item.NzbUrl())
+	if len(ref) == 0 {
+		return nil, errors.New("newznab: item has no enclosure or link")
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if base, err := url.Parse(c.url); err == nil {
+		u = base.ResolveReference(u)
+		if strings.EqualFold(u.Host, base.Host) && len(c.key) > 0 {
+			q := u.Query()
+			if len(q.Get("apikey")) == 0 && len(q.Get("r")) == 0 {
+				q.Set("apikey", c.key)
+				u.RawQuery = q.Encode()
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// GetNzbParsed retrieves an NZB file and returns it decoded.
+func (c *Client) GetNzbParsed(id string) (*Nzb, error) {
+	return c.GetNzbParsedContext(context.Background(), id)
+}
+
+// GetNzbParsedContext retrieves an NZB file using the provided context and returns it decoded.
+func (c *Client) GetNzbParsedContext(ctx context.Context, id string) (*Nzb, error) {
+	raw, err := c.GetNzbRawContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Don't bother decoding if the caller has given up.
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	nzb, err := fromXml(raw.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &nzb, nil
+}
+
+// GetNzbRaw retrieves an NZB file and returns its original bytes along with the details sent in the headers.
+func (c *Client) GetNzbRaw(id string) (*RawNzb, error) {
+	return c.GetNzbRawContext(context.Background(), id)
+}
+
+// GetNzbRawContext retrieves an NZB file using the provided context and returns its original bytes.
+func (c *Client) GetNzbRawContext(ctx context.Context, id string) (*RawNzb, error) {
+	u, err := EncodeUrl(c.url, Apikey(c.key), nzbid(id), Type("get"))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	info, err := c.download(ctx, u, &buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RawNzb{NzbInfo: *info, Data: buf.Bytes()}, nil
+}
+
+// GetNzbTo retrieves an NZB file and writes it to w as it is received, without holding it in memory.
+func (c *Client) GetNzbTo(id string, w io.Writer) (*NzbInfo, error) {
+	return c.GetNzbToContext(context.Background(), id, w)
+}
+
+// GetNzbToContext retrieves an NZB file using the provided context and writes it to w as it is received.
+func (c *Client) GetNzbToContext(ctx context.Context, id string, w io.Writer) (*NzbInfo, error) {
+	u, err := EncodeUrl(c.url, Apikey(c.key), nzbid(id), Type("get"))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.download(ctx, u, w)
+}
+
+// download performs a GET operation and copies the body to w. Nothing is written if the server responds with an
+// error, so failures before the copy starts are retried according to the client's RetryPolicy. Downloads are only
+// cached when a TTL has been set for "get".
+func (c *Client) download(ctx context.Context, u *url.URL, w io.Writer) (*NzbInfo, error) {
+	if entry, ok := c.cached(ctx, u, "get"); ok {
+		if _, err := w.Write(entry.Body); err != nil {
+			return nil, err
+		}
+		return nzbInfo(entry.Header), nil
+	}
+
+	var res *http.Response
+	var reader *bufio.Reader
+	err := c.retry(ctx, u, func() (http.Header, error) {
+		var err error
+		res, err = c.open(ctx, u, true)
+		if err != nil {
+			return nil, err
+		}
+
+		// Look at the start of the body for an error document before writing anything.
+		reader = bufio.NewReaderSize(res.Body, peekSize)
+		head, err := reader.Peek(peekSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			res.Body.Close()
+			return res.Header, err
+		}
+
+		if err = checkResponse(res, head); err != nil {
+			res.Body.Close()
+			return res.Header, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// Keep a copy of the file if it's going to be cached.
+	var buf *bytes.Buffer
+	if c.cacheTTL("get") > 0 {
+		buf = new(bytes.Buffer)
+		w = io.MultiWriter(w, buf)
+	}
+
+	if _, err = io.Copy(w, reader); err != nil {
+		return nil, err
+	}
+
+	if buf != nil {
+		c.store(u, "get", buf.Bytes(), res.Header)
+	}
+	return nzbInfo(res.Header), nil
+}
+
+// nzbInfo returns the details of an NZB file found in the response headers.
+func nzbInfo(header http.Header) *NzbInfo {
+	info := &NzbInfo{
+		Name:     header.Get("X-DNZB-Name"),
+		Category: header.Get("X-DNZB-Category"),
+		Password: header.Get("X-DNZB-Password"),
+		Header:   make(http.Header),
+	}
+
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		info.Filename = params["filename"]
+	}
+
+	for name, values := range header {
+		if strings.HasPrefix(strings.ToUpper(name), "X-DNZB-") {
+			info.Header[name] = values
+		}
+	}
+
+	return info
+}
+
+// GetNzbParsed retrieves an NZB file and returns it decoded.
+func GetNzbParsed(url string, key string, id string) (*Nzb, error) {
+	return newClient(url, key).GetNzbParsed(id)
+}
+
+// GetNzbParsedContext retrieves an NZB file using the provided context and returns it decoded.
+func GetNzbParsedContext(ctx context.Context, url string, key string, id string) (*Nzb, error) {
+	return newClient(url, key).GetNzbParsedContext(ctx, id)
+}
+
+// GetNzbRaw retrieves an NZB file and returns its original bytes along with the details sent in the headers.
+func GetNzbRaw(url string, key string, id string) (*RawNzb, error) {
+	return newClient(url, key).GetNzbRaw(id)
+}
+
+// GetNzbRawContext retrieves an NZB file using the provided context and returns its original bytes.
+func GetNzbRawContext(ctx context.Context, url string, key string, id string) (*RawNzb, error) {
+	return newClient(url, key).GetNzbRawContext(ctx, id)
+}
+
+// GetNzbTo retrieves an NZB file and writes it to w as it is received.
+func GetNzbTo(url string, key string, id string, w io.Writer) (*NzbInfo, error) {
+	return newClient(url, key).GetNzbTo(id, w)
+}
+
+// GetNzbToContext retrieves an NZB file using the provided context and writes it to w as it is received.
+func GetNzbToContext(ctx context.Context, url string, key string, id string, w io.Writer) (*NzbInfo, error) {
+	return newClient(url, key).GetNzbToContext(ctx, id, w)
+}
@@ -0,0 +1,136 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"errors"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func nzbServer(t *testing.T) *httptest.Server {
+	data, err := ioutil.ReadFile("testdata/nzb-short.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "1a2b3c" {
+			_, _ = w.Write([]byte(`<error code="300" description="No such item"/>`))
+			return
+		}
+		w.Header().Set("Content-Disposition", `attachment; filename="The.Office.US.S02E22.nzb"`)
+		w.Header().Set("X-DNZB-Name", "The.Office.US.S02E22")
+		w.Header().Set("X-DNZB-Category", "TV > HD")
+		w.Header().Set("X-DNZB-Password", "secret")
+		w.Header().Set("X-DNZB-MoreInfo", "http://example.com/info")
+		_, _ = w.Write(data)
+	}))
+}
+
+func TestGetNzbRaw(t *testing.T) {
+	server := nzbServer(t)
+	defer server.Close()
+
+	data, _ := ioutil.ReadFile("testdata/nzb-short.xml")
+
+	c := NewClient(WithUrl(server.URL+"/api"), WithApikey("key"))
+	raw, err := c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(bytes.Equal(raw.Data, data)).IsEqualTo(true)
+	assert.With(t).That(raw.Filename).IsEqualTo("The.Office.US.S02E22.nzb")
+	assert.With(t).That(raw.Name).IsEqualTo("The.Office.US.S02E22")
+	assert.With(t).That(raw.Category).IsEqualTo("TV > HD")
+	assert.With(t).That(raw.Password).IsEqualTo("secret")
+	assert.With(t).That(raw.Header.Get("X-DNZB-MoreInfo")).IsEqualTo("http://example.com/info")
+	assert.With(t).That(raw.Header.Get("Content-Disposition")).IsEmpty()
+}
+
+func TestGetNzbTo(t *testing.T) {
+	server := nzbServer(t)
+	defer server.Close()
+
+	data, _ := ioutil.ReadFile("testdata/nzb-short.xml")
+
+	var buf bytes.Buffer
+	info, err := GetNzbTo(server.URL+"/api", "key", "1a2b3c", &buf)
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(info.Filename).IsEqualTo("The.Office.US.S02E22.nzb")
+	assert.With(t).That(bytes.Equal(buf.Bytes(), data)).IsEqualTo(true)
+
+	// Nothing is written when the server returns an error.
+	buf.Reset()
+	_, err = GetNzbTo(server.URL+"/api", "key", "missing", &buf)
+	assert.With(t).That(errors.Is(err, ErrNoSuchItem)).IsEqualTo(true)
+	assert.With(t).That(buf.Len()).IsEqualTo(0)
+}
+
+func TestGetNzbParsed(t *testing.T) {
+	server := nzbServer(t)
+	defer server.Close()
+
+	nzb, err := GetNzbParsed(server.URL+"/api", "key", "1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(len(nzb.File) > 0).IsEqualTo(true)
+}
+
+func TestDownloadNzb(t *testing.T) {
+	data, _ := ioutil.ReadFile("testdata/nzb-short.xml")
+
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/getnzb/1a2b3c.nzb":
+			http.Redirect(w, r, "/files/1a2b3c.nzb", http.StatusFound)
+		case "/files/1a2b3c.nzb":
+			w.Header().Set("X-DNZB-Name", "The.Office.US.S02E22")
+			_, _ = w.Write(data)
+		default:
+			_, _ = w.Write([]byte(`<error code="300" description="No such item"/>`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL+"/api"), WithApikey("key"))
+
+	// The enclosure already carries the key, so it's used as is.
+	var item Item
+	item.Enclosure.URL = server.URL + "/getnzb/1a2b3c.nzb?r=key"
+	raw, err := c.DownloadNzb(item)
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(raw.Name).IsEqualTo("The.Office.US.S02E22")
+	assert.With(t).That(bytes.Equal(raw.Data, data)).IsEqualTo(true)
+
+	// A relative link is resolved against the client's URL and given the key.
+	var buf bytes.Buffer
+	_, err = c.DownloadNzbTo(Item{Link: "/getnzb/missing.nzb"}, &buf)
+	assert.With(t).That(errors.Is(err, ErrNoSuchItem)).IsEqualTo(true)
+	assert.With(t).That(buf.Len()).IsEqualTo(0)
+
+	_, err = c.DownloadNzb(Item{})
+	assert.With(t).That(err).IsNotNil()
+
+	assert.With(t).That(len(requests)).IsEqualTo(3)
+	assert.With(t).That(requests[0]).IsEqualTo("/getnzb/1a2b3c.nzb?r=key")
+	assert.With(t).That(requests[1]).IsEqualTo("/files/1a2b3c.nzb?")
+	assert.With(t).That(requests[2]).IsEqualTo("/getnzb/missing.nzb?apikey=key")
+}
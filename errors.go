@@ -0,0 +1,189 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"strconv"
+	"strings"
+)
+
+// APIError is returned when the server responds with a Newznab error document such as
+// <error code="100" description="Incorrect user credentials"/>. Servers usually send these with an HTTP 200 status.
+type APIError struct {
+	Code        int
+	Description string
+}
+
+// StatusError is returned when the server responds with an HTTP status other than 200 OK and no Newznab error
+// document.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+// Errors defined by the Newznab API specification. Use errors.Is to test an error returned by this library against
+// these values; only the Code is compared.
+var (
+	// Account errors.
+	ErrIncorrectCredentials   = &APIError{Code: 100, Description: "Incorrect user credentials"}
+	ErrAccountSuspended       = &APIError{Code: 101, Description: "Account suspended"}
+	ErrInsufficientPrivileges = &APIError{Code: 102, Description: "Insufficient privileges/not authorized"}
+	ErrRegistrationDenied     = &APIError{Code: 103, Description: "Registration denied"}
+	ErrRegistrationClosed     = &APIError{Code: 104, Description: "Registrations are closed"}
+	ErrEmailTaken             = &APIError{Code: 105, Description: "Invalid registration (email address taken)"}
+	ErrEmailInvalid           = &APIError{Code: 106, Description: "Invalid registration (email address bad format)"}
+	ErrRegistrationFailed     = &APIError{Code: 107, Description: "Registration failed (data error)"}
+
+	// Request errors.
+	ErrMissingParameter    = &APIError{Code: 200, Description: "Missing parameter"}
+	ErrIncorrectParameter  = &APIError{Code: 201, Description: "Incorrect parameter"}
+	ErrNoSuchFunction      = &APIError{Code: 202, Description: "No such function"}
+	ErrFunctionUnavailable = &APIError{Code: 203, Description: "Function not available"}
+
+	// Item errors.
+	ErrNoSuchItem = &APIError{Code: 300, Description: "No such item"}
+	ErrItemExists = &APIError{Code: 310, Description: "Item already exists"}
+
+	// Limit errors. Servers based on nZEDb report the request limit as ErrTooManyRequests; it also matches
+	// ErrRequestLimitReached.
+	ErrRequestLimitReached  = &APIError{Code: 500, Description: "Request limit reached"}
+	ErrDownloadLimitReached = &APIError{Code: 501, Description: "Download limit reached"}
+	ErrTooManyRequests      = &APIError{Code: 429, Description: "Request limit reached"}
+
+	// Other errors.
+	ErrUnknown     = &APIError{Code: 900, Description: "Unknown error"}
+	ErrApiDisabled = &APIError{Code: 910, Description: "API disabled"}
+)
+
+// Error returns the code and description reported by the server.
+func (e *APIError) Error() string {
+	if len(e.Description) == 0 {
+		return fmt.Sprintf("newznab: error %d", e.Code)
+	}
+	return fmt.Sprintf("newznab: error %d: %s", e.Code, e.Description)
+}
+
+// Error returns the status line sent by the server, such as "502 Bad Gateway".
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+// Is reports whether target is an *APIError with the same Code. The request limit codes 429 and 500 are treated as
+// the same.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && canonicalCode(t.Code) == canonicalCode(e.Code)
+}
+
+// canonicalCode maps the codes that different servers use for the same error to one value.
+func canonicalCode(code int) int {
+	if code == ErrTooManyRequests.Code {
+		return ErrRequestLimitReached.Code
+	}
+	return code
+}
+
+// parseError returns an *APIError if the body is a Newznab error document in either XML or JSON format. Any other
+// content, including documents that can't be parsed, returns nil.
+func parseError(body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	if trimmed[0] == '{' {
+		return parseJsonError(trimmed)
+	}
+	return parseXmlError(trimmed)
+}
+
+// parseXmlError looks at the root element of an XML document and returns an *APIError if it is <error>.
+func parseXmlError(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		// Only the root element matters.
+		if start.Name.Local != "error" {
+			return nil
+		}
+
+		e := &APIError{}
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "code":
+				e.Code, _ = strconv.Atoi(strings.TrimSpace(attr.Value))
+			case "description":
+				e.Description = attr.Value
+			}
+		}
+		return e
+	}
+}
+
+// parseJsonError returns an *APIError if the JSON object has an "error" member. Implementations differ in whether the
+// code and description are wrapped in "@attributes", and whether the code is a string or a number.
+func parseJsonError(body []byte) error {
+	var doc struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil || len(doc.Error) == 0 {
+		return nil
+	}
+
+	var fields struct {
+		Attributes  *jsonErrorFields `json:"@attributes"`
+		Code        json.RawMessage  `json:"code"`
+		Description string           `json:"description"`
+	}
+	if err := json.Unmarshal(doc.Error, &fields); err != nil {
+		// Some servers send the description on its own.
+		var description string
+		if json.Unmarshal(doc.Error, &description) != nil {
+			return nil
+		}
+		return &APIError{Code: ErrUnknown.Code, Description: description}
+	}
+
+	if fields.Attributes != nil {
+		fields.Code = fields.Attributes.Code
+		fields.Description = fields.Attributes.Description
+	}
+
+	code, _ := strconv.Atoi(strings.Trim(string(fields.Code), "\" "))
+	return &APIError{Code: code, Description: fields.Description}
+}
+
+// jsonErrorFields holds the contents of the "@attributes" wrapper.
+type jsonErrorFields struct {
+	Code        json.RawMessage `json:"code"`
+	Description string          `json:"description"`
+}
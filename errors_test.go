@@ -0,0 +1,73 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"errors"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseXmlError(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<error code="100" description="Incorrect user credentials"/>`
+
+	err := parseError([]byte(body))
+	assert.With(t).That(errors.Is(err, ErrIncorrectCredentials)).IsEqualTo(true)
+
+	var apiErr *APIError
+	assert.With(t).That(errors.As(err, &apiErr)).IsEqualTo(true)
+	assert.With(t).That(apiErr.Code).IsEqualTo(100)
+	assert.With(t).That(apiErr.Description).IsEqualTo("Incorrect user credentials")
+}
+
+func TestParseJsonError(t *testing.T) {
+	err := parseError([]byte(`{"error":{"@attributes":{"code":"429","description":"Request limit reached"}}}`))
+	assert.With(t).That(errors.Is(err, ErrRequestLimitReached)).IsEqualTo(true)
+
+	err = parseError([]byte(`{"error":{"code":201,"description":"Incorrect parameter"}}`))
+	assert.With(t).That(errors.Is(err, ErrIncorrectParameter)).IsEqualTo(true)
+}
+
+func TestLimitErrorCodes(t *testing.T) {
+	err := parseError([]byte(`<error code="500" description="Request limit reached"/>`))
+	assert.With(t).That(errors.Is(err, ErrRequestLimitReached)).IsEqualTo(true)
+	assert.With(t).That(errors.Is(err, ErrTooManyRequests)).IsEqualTo(true)
+	assert.With(t).That(errors.Is(err, ErrDownloadLimitReached)).IsEqualTo(false)
+
+	err = parseError([]byte(`<error code="501" description="Download limit reached"/>`))
+	assert.With(t).That(errors.Is(err, ErrDownloadLimitReached)).IsEqualTo(true)
+	assert.With(t).That(errors.Is(err, ErrRequestLimitReached)).IsEqualTo(false)
+}
+
+func TestParseErrorIgnoresResults(t *testing.T) {
+	assert.With(t).That(parseError([]byte(`<?xml version="1.0"?><rss version="2.0"></rss>`))).IsNil()
+	assert.With(t).That(parseError([]byte(`{"channel":{}}`))).IsNil()
+	assert.With(t).That(parseError([]byte(``))).IsNil()
+}
+
+func TestClientReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<error code="101" description="Account suspended"/>`))
+	}))
+	defer server.Close()
+
+	_, err := Search(server.URL, "key", Query("test"))
+	assert.With(t).That(errors.Is(err, ErrAccountSuspended)).IsEqualTo(true)
+}
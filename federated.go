@@ -0,0 +1,165 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Indexer is a named server queried as part of a Federation.
+type Indexer struct {
+	// Name identifies the indexer in results and errors. It must be unique within a Federation.
+	Name string
+
+	// Client is used to make requests to the indexer.
+	Client *Client
+
+	// Timeout limits the time spent waiting for this indexer. Zero means no limit beyond the caller's context.
+	Timeout time.Duration
+
+	// Priority ranks indexers when choosing between duplicate releases. Higher values are preferred.
+	Priority int
+}
+
+// Federation runs the same search against several indexers at once.
+type Federation struct {
+	indexers []Indexer
+}
+
+// FederatedItem is an Item tagged with the name of the indexer that returned it.
+type FederatedItem struct {
+	Item
+	Indexer string
+}
+
+// FederatedResult contains the items merged from every indexer that responded, and the errors from those that
+// didn't.
+type FederatedResult struct {
+	Items  []FederatedItem
+	Errors map[string]error
+
+	// indexers is the number of indexers that were searched.
+	indexers int
+}
+
+// NewFederation returns a Federation that searches the given indexers.
+func NewFederation(indexers ...Indexer) *Federation {
+	return &Federation{indexers: indexers}
+}
+
+// Indexers returns the indexers searched by the Federation.
+func (f *Federation) Indexers() []Indexer {
+	return f.indexers
+}
+
+// BookSearch performs a search restricted to e-books on every indexer.
+func (f *Federation) BookSearch(ctx context.Context, params ...Param) *FederatedResult {
+	return f.search(ctx, "book", params)
+}
+
+// MovieSearch performs a search restricted to movies on every indexer.
+func (f *Federation) MovieSearch(ctx context.Context, params ...Param) *FederatedResult {
+	return f.search(ctx, "movie", params)
+}
+
+// MusicSearch performs a search restricted to music on every indexer.
+func (f *Federation) MusicSearch(ctx context.Context, params ...Param) *FederatedResult {
+	return f.search(ctx, "music", params)
+}
+
+// Search performs a general search on every indexer.
+func (f *Federation) Search(ctx context.Context, params ...Param) *FederatedResult {
+	return f.search(ctx, "search", params)
+}
+
+// TvSearch performs a search restricted to TV shows on every indexer.
+func (f *Federation) TvSearch(ctx context.Context, params ...Param) *FederatedResult {
+	return f.search(ctx, "tvsearch", params)
+}
+
+// search queries each indexer concurrently and merges the results in the order the indexers were configured.
+func (f *Federation) search(ctx context.Context, t string, params []Param) *FederatedResult {
+	pages := make([]*Newznab, len(f.indexers))
+	errs := make([]error, len(f.indexers))
+
+	var wg sync.WaitGroup
+	for i := range f.indexers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pages[i], errs[i] = f.indexers[i].search(ctx, t, params)
+		}(i)
+	}
+	wg.Wait()
+
+	res := &FederatedResult{
+		Items:    make([]FederatedItem, 0),
+		Errors:   make(map[string]error),
+		indexers: len(f.indexers),
+	}
+	for i, indexer := range f.indexers {
+		if errs[i] != nil {
+			res.Errors[indexer.Name] = errs[i]
+			continue
+		}
+		for _, item := range pages[i].Channel.Item {
+			res.Items = append(res.Items, FederatedItem{Item: item, Indexer: indexer.Name})
+		}
+	}
+
+	return res
+}
+
+// search runs a single search against the indexer, applying its timeout.
+func (i Indexer) search(ctx context.Context, t string, params []Param) (*Newznab, error) {
+	if i.Client == nil {
+		return nil, errors.New("newznab: indexer " + i.Name + " has no client")
+	}
+
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
+	}
+
+	return i.Client.searchParsed(ctx, t, params...)
+}
+
+// Err returns an error only when every indexer failed. Check Errors for the indexers that failed when some results
+// were returned.
+func (r *FederatedResult) Err() error {
+	if r.indexers == 0 || len(r.Errors) < r.indexers {
+		return nil
+	}
+
+	names := make([]string, 0, len(r.Errors))
+	for name := range r.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + r.Errors[name].Error()
+	}
+	return errors.New("newznab: every indexer failed: " + strings.Join(msgs, "; "))
+}
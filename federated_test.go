@@ -0,0 +1,60 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFederationSearch(t *testing.T) {
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<rss><channel><item><title>One</title></item><item><title>Two</title></item></channel></rss>`))
+	}))
+	defer good.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	failed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<error code="100" description="Incorrect user credentials"/>`))
+	}))
+	defer failed.Close()
+
+	f := NewFederation(
+		Indexer{Name: "good", Client: NewClient(WithUrl(good.URL))},
+		Indexer{Name: "slow", Client: NewClient(WithUrl(slow.URL)), Timeout: 50 * time.Millisecond},
+		Indexer{Name: "failed", Client: NewClient(WithUrl(failed.URL))})
+
+	res := f.Search(context.Background(), Query("test"))
+	assert.With(t).That(res.Err()).IsNil()
+	assert.With(t).That(len(res.Items)).IsEqualTo(2)
+	assert.With(t).That(res.Items[1].Item.Title).IsEqualTo("Two")
+	assert.With(t).That(res.Items[1].Indexer).IsEqualTo("good")
+	assert.With(t).That(len(res.Errors)).IsEqualTo(2)
+	assert.With(t).That(res.Errors["slow"]).IsNotNil()
+	assert.With(t).That(res.Errors["failed"]).IsNotNil()
+}
@@ -0,0 +1,103 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Feeds of the user's own lists, used in place of a Category when calling Feed.
+var (
+	MyCart   = Category{id: "-1"}
+	MyShows  = Category{id: "-2"}
+	MyMovies = Category{id: "-3"}
+)
+
+// WithRssUrl returns an Option that sets the full URL of the server's RSS feeds. By default it is derived from the API
+// URL by replacing the final "api" path segment with "rss".
+func WithRssUrl(url string) Option {
+	return func(c *Client) {
+		c.rssUrl = url
+	}
+}
+
+// Feed returns the server's RSS feed of the newest releases in a category, or one of the user's own lists such as
+// MyCart. Feeds are cheaper than searches and usually don't count against the API limit. The user's ID, shown on the
+// indexer's RSS help page, and the API key identify the user; the ID may be empty for servers that don't require it.
+func (c *Client) Feed(cat Category, uid string, params ...Param) (*Newznab, error) {
+	return c.FeedContext(context.Background(), cat, uid, params...)
+}
+
+// FeedContext returns the server's RSS feed of a category using the provided context.
+func (c *Client) FeedContext(ctx context.Context, cat Category, uid string, params ...Param) (*Newznab, error) {
+	base, err := c.feedUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	p := append(params, Type(cat.id), RssKey(c.key))
+	if len(uid) > 0 {
+		p = append(p, UserId(uid))
+	}
+
+	u, err := EncodeUrl(base, p...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeNewznab(res.header.Get("Content-Type"), res.body)
+}
+
+// feedUrl returns the URL of the server's RSS feeds.
+func (c *Client) feedUrl() (string, error) {
+	if len(c.rssUrl) > 0 {
+		return c.rssUrl, nil
+	}
+
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return "", err
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if strings.HasSuffix(path, "/api") || path == "api" {
+		path = strings.TrimSuffix(path, "api") + "rss"
+	} else {
+		path += "/rss"
+	}
+	u.Path = path
+	u.RawQuery = ""
+
+	return u.String(), nil
+}
+
+// Feed returns the server's RSS feed of the newest releases in a category, or one of the user's own lists.
+func Feed(url string, key string, cat Category, uid string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).Feed(cat, uid, params...)
+}
+
+// FeedContext returns the server's RSS feed of a category using the provided context.
+func FeedContext(ctx context.Context, url string, key string, cat Category, uid string, params ...Param) (*Newznab, error) {
+	return newClient(url, key).FeedContext(ctx, cat, uid, params...)
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFeed(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<rss><channel><item><title>The.Office.US.S02E22.720p.WEB-DL</title></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	feed, err := Feed(server.URL+"/api", "key", TV_HD, "38759", Num(50), Download())
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(len(feed.Channel.Item)).IsEqualTo(1)
+	assert.With(t).That(path).IsEqualTo("/rss")
+	assert.With(t).That(query).IsEqualTo("dl=1&i=38759&num=50&r=key&t=5040")
+
+	_, err = Feed(server.URL+"/api", "key", MyShows, "", Delete())
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(query).IsEqualTo("del=1&r=key&t=-2")
+}
+
+func TestFeedUrl(t *testing.T) {
+	urls := map[string]string{
+		"https://example.com/api":       "https://example.com/rss",
+		"https://example.com/api/":      "https://example.com/rss",
+		"https://example.com/nzb/api":   "https://example.com/nzb/rss",
+		"https://example.com/newznab":   "https://example.com/newznab/rss",
+		"https://example.com/api?o=xml": "https://example.com/rss",
+	}
+
+	for api, rss := range urls {
+		actual, err := NewClient(WithUrl(api)).feedUrl()
+		assert.With(t).That(err).IsNil()
+		assert.With(t).That(actual).IsEqualTo(rss)
+	}
+
+	actual, _ := NewClient(WithUrl("https://example.com/api"), WithRssUrl("https://rss.example.com/")).feedUrl()
+	assert.With(t).That(actual).IsEqualTo("https://rss.example.com/")
+}
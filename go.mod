@@ -5,4 +5,5 @@ go 1.15
 require (
 	github.com/MediaExchange/assert v1.0.0
 	golang.org/x/net v0.0.0-20210119194325-5f4716e94777
+	golang.org/x/text v0.3.3
 )
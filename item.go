@@ -0,0 +1,186 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item is a single release in the results of a query. The fields hold the values exactly as they were sent by the
+// server; use the methods to read them as typed values.
+type Item struct {
+	Text  string `xml:",chardata" json:"-"`
+	Title string `xml:"title"`
+	Guid  struct {
+		Text        string `xml:",chardata"`
+		IsPermaLink string `xml:"isPermaLink,attr"`
+	} `xml:"guid"`
+	Link        string `xml:"link"`
+	Comments    string `xml:"comments"`
+	PubDate     string `xml:"pubDate"`
+	Category    string `xml:"category"`
+	Description string `xml:"description"`
+	Enclosure   struct {
+		Text   string `xml:",chardata" json:"-"`
+		URL    string `xml:"url,attr"`
+		Length string `xml:"length,attr"`
+		Type   string `xml:"type,attr"`
+	} `xml:"enclosure"`
+	Attr []Attr `xml:"attr"`
+}
+
+// Attr is a name-value pair from a newznab:attr or torznab:attr element.
+type Attr struct {
+	XMLName xml.Name `json:"-"`
+	Text    string   `xml:",chardata" json:"-"`
+	Name    string   `xml:"name,attr"`
+	Value   string   `xml:"value,attr"`
+}
+
+// Password describes whether the files of a release are protected by a password.
+type Password int
+
+const (
+	// PasswordUnknown means the server didn't report the password status.
+	PasswordUnknown Password = -1
+
+	// PasswordNone means the release isn't protected.
+	PasswordNone Password = 0
+
+	// PasswordRar means the RAR archives require a password.
+	PasswordRar Password = 1
+
+	// PasswordInnerArchive means the release contains an inner archive that may be protected.
+	PasswordInnerArchive Password = 2
+)
+
+// AttrValue returns the value of the first attribute with the given name, or an empty string if there isn't one.
+func (i Item) AttrValue(name string) string {
+	for _, attr := range i.Attr {
+		if attr.Name == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// AttrValues returns the values of every attribute with the given name, such as "category".
+func (i Item) AttrValues(name string) []string {
+	values := make([]string, 0)
+	for _, attr := range i.Attr {
+		if attr.Name == name {
+			values = append(values, attr.Value)
+		}
+	}
+	return values
+}
+
+// Categories returns the IDs of every category the item belongs to.
+func (i Item) Categories() []string {
+	return i.AttrValues("category")
+}
+
+// CommentCount returns the number of comments posted about the item.
+func (i Item) CommentCount() int {
+	return i.intAttr("comments")
+}
+
+// Files returns the number of files in the release.
+func (i Item) Files() int {
+	return i.intAttr("files")
+}
+
+// Grabs returns the number of times the item has been downloaded.
+func (i Item) Grabs() int {
+	return i.intAttr("grabs")
+}
+
+// Id returns the GUID used to refer to the item in API calls such as GetDetails and GetNzb. It is taken from the
+// "guid" attribute, or from the end of the guid URL when the attribute is missing.
+func (i Item) Id() string {
+	if id := i.AttrValue("guid"); len(id) > 0 {
+		return id
+	}
+
+	guid := strings.TrimRight(strings.TrimSpace(i.Guid.Text), "/")
+	return guid[strings.LastIndex(guid, "/")+1:]
+}
+
+// NzbUrl returns the URL of the item's NZB file: the enclosure if there is one, otherwise the link.
+func (i Item) NzbUrl() string {
+	if len(i.Enclosure.URL) > 0 {
+		return i.Enclosure.URL
+	}
+	return i.Link
+}
+
+// Password returns whether the release is protected by a password.
+func (i Item) Password() Password {
+	p, err := strconv.Atoi(i.AttrValue("password"))
+	if err != nil {
+		return PasswordUnknown
+	}
+	return Password(p)
+}
+
+// Published returns the date the item was added to the indexer, or the zero time if it can't be parsed.
+func (i Item) Published() time.Time {
+	t, _ := parseDate(i.PubDate)
+	return t
+}
+
+// Size returns the size of the release in bytes, or zero if it isn't known.
+func (i Item) Size() int64 {
+	size, err := strconv.ParseInt(i.AttrValue("size"), 10, 64)
+	if err != nil {
+		size, _ = strconv.ParseInt(i.Enclosure.Length, 10, 64)
+	}
+	return size
+}
+
+// UsenetDate returns the date the release was posted to Usenet, or the zero time if it isn't known.
+func (i Item) UsenetDate() time.Time {
+	t, _ := parseDate(i.AttrValue("usenetdate"))
+	return t
+}
+
+// IsTorznab reports whether the attribute came from a torznab:attr element.
+func (a Attr) IsTorznab() bool {
+	return a.XMLName.Space == TorznabNamespace || a.XMLName.Space == "torznab"
+}
+
+// intAttr returns the value of a numeric attribute, or zero if it isn't present.
+func (i Item) intAttr(name string) int {
+	n, _ := strconv.Atoi(i.AttrValue(name))
+	return n
+}
+
+// parseDate parses the RFC 822 dates used by RSS feeds.
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var t time.Time
+	var err error
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", time.RFC3339} {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return t, err
+}
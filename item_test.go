@@ -0,0 +1,105 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestItemAccessors(t *testing.T) {
+	original, err := ioutil.ReadFile("testdata/search-results.xml")
+	if err != nil {
+		t.Error(err)
+	}
+
+	newznab, err := NewznabFromXml(original)
+	assert.With(t).That(err).IsNil()
+
+	item := newznab.Channel.Item[0]
+	assert.With(t).That(item.Size()).IsEqualTo(int64(4405684482))
+	assert.With(t).That(item.Files()).IsEqualTo(9)
+	assert.With(t).That(item.Grabs()).IsEqualTo(1)
+	assert.With(t).That(item.CommentCount()).IsEqualTo(0)
+	assert.With(t).That(int(item.Password())).IsEqualTo(int(PasswordNone))
+	assert.With(t).That(len(item.Categories())).IsEqualTo(2)
+	assert.With(t).That(item.Categories()[1]).IsEqualTo("5070")
+	assert.With(t).That(item.AttrValue("guid")).IsEqualTo("cde1b96cd017143e716926cf5152bcc7")
+	assert.With(t).That(item.AttrValue("missing")).IsEmpty()
+
+	published := time.Date(2020, time.November, 18, 3, 42, 40, 0, time.UTC)
+	assert.With(t).That(item.Published().Equal(published)).IsEqualTo(true)
+
+	posted := time.Date(2020, time.November, 17, 23, 41, 48, 0, time.UTC)
+	assert.With(t).That(item.UsenetDate().Equal(posted)).IsEqualTo(true)
+}
+
+func TestItemMissingAttrs(t *testing.T) {
+	var item Item
+	assert.With(t).That(item.Size()).IsEqualTo(int64(0))
+	assert.With(t).That(int(item.Password())).IsEqualTo(int(PasswordUnknown))
+	assert.With(t).That(item.Published().IsZero()).IsEqualTo(true)
+	assert.With(t).That(len(item.Categories())).IsEqualTo(0)
+}
+
+func TestItemMedia(t *testing.T) {
+	data := `<rss><channel><item>
+<newznab:attr name="tvtitle" value="The Office (US)"/>
+<newznab:attr name="rageid" value="6061"/>
+<newznab:attr name="tvdbid" value="73244"/>
+<newznab:attr name="season" value="S02"/>
+<newznab:attr name="episode" value="E22"/>
+<newznab:attr name="tvairdate" value="Thu, 11 May 2006 00:00:00 +0000"/>
+<newznab:attr name="imdb" value="0386676"/>
+<newznab:attr name="imdbtitle" value="The Office"/>
+<newznab:attr name="imdbyear" value="2005"/>
+<newznab:attr name="imdbscore" value="8.9"/>
+<newznab:attr name="artist" value="Ella Fitzgerald"/>
+<newznab:attr name="tracks" value="Summertime | Cheek to Cheek"/>
+<newznab:attr name="booktitle" value="The Hobbit"/>
+<newznab:attr name="pages" value="310"/>
+<newznab:attr name="publishdate" value="1937-09-21"/>
+</item></channel></rss>`
+
+	newznab, err := NewznabFromXml([]byte(data))
+	assert.With(t).That(err).IsNil()
+	item := newznab.Channel.Item[0]
+
+	tv := item.Tv()
+	assert.With(t).That(tv.Title).IsEqualTo("The Office (US)")
+	assert.With(t).That(tv.TvdbId).IsEqualTo(73244)
+	assert.With(t).That(tv.Season).IsEqualTo(2)
+	assert.With(t).That(tv.Episode).IsEqualTo(22)
+	assert.With(t).That(tv.AirDate.Year()).IsEqualTo(2006)
+
+	movie := item.Movie()
+	assert.With(t).That(movie.ImdbId).IsEqualTo("0386676")
+	assert.With(t).That(movie.Year).IsEqualTo(2005)
+	assert.With(t).That(movie.Score).IsEqualTo(8.9)
+
+	music := item.Music()
+	assert.With(t).That(music.Artist).IsEqualTo("Ella Fitzgerald")
+	assert.With(t).That(len(music.Tracks)).IsEqualTo(2)
+	assert.With(t).That(music.Tracks[1]).IsEqualTo("Cheek to Cheek")
+
+	book := item.Book()
+	assert.With(t).That(book.Title).IsEqualTo("The Hobbit")
+	assert.With(t).That(book.Pages).IsEqualTo(310)
+	assert.With(t).That(book.PublishDate.Year()).IsEqualTo(1937)
+}
@@ -0,0 +1,196 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strconv"
+)
+
+// Names used by the various server implementations for the same elements. The XML element names come first, followed
+// by the names produced by the JSON encoders of nZEDb, NNTmux and NZBHydra.
+var (
+	jsonAtomLinkNames = []string{"atom:link", "atom_link", "atomLink"}
+	jsonResponseNames = []string{"response", "newznab:response", "newznab_response", "newznabResponse"}
+	jsonAttrNames     = []string{"attr", "newznab:attr", "newznab_attr", "newznabAttributes"}
+	jsonTorznabNames  = []string{"torznab:attr", "torznab_attr", "torznabAttributes"}
+	jsonTextNames     = []string{"#text", "text", "_text", "$", "_", "value"}
+)
+
+// NewznabFromJson decodes Newznab JSON content, as returned when the Json() parameter is used, to a Newznab struct.
+// Servers encode JSON differently, so this accepts attributes wrapped in "@attributes" or prefixed with "@" or "_",
+// single objects in place of arrays, and numbers in place of strings. The result is the same as decoding the equivalent
+// XML with NewznabFromXml.
+func NewznabFromJson(data []byte) (newznab Newznab, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err = decoder.Decode(&doc); err != nil {
+		return
+	}
+
+	root := jsonObject(doc)
+	if rss := jsonObject(root["rss"]); rss != nil {
+		root = rss
+	}
+
+	channel := jsonObject(root["channel"])
+	if channel == nil {
+		err = errors.New("newznab: JSON document has no channel")
+		return
+	}
+
+	newznab.Version = jsonAttr(root, "version")
+	if len(newznab.Version) == 0 {
+		newznab.Version = "2.0"
+	}
+
+	c := &newznab.Channel
+	c.Title = jsonText(channel["title"])
+	c.Description = jsonText(channel["description"])
+	c.Language = jsonText(channel["language"])
+	c.WebMaster = jsonText(channel["webMaster"])
+	c.Category = jsonText(channel["category"])
+
+	c.Link.Text = jsonText(channel["link"])
+	if link := jsonObject(jsonField(channel, jsonAtomLinkNames...)); link != nil {
+		c.Link.Href = jsonAttr(link, "href")
+		c.Link.Rel = jsonAttr(link, "rel")
+		c.Link.Type = jsonAttr(link, "type")
+	}
+
+	if image := jsonObject(channel["image"]); image != nil {
+		c.Image.URL = jsonText(image["url"])
+		c.Image.Title = jsonText(image["title"])
+		c.Image.Link = jsonText(image["link"])
+		c.Image.Description = jsonText(image["description"])
+	}
+
+	if response := jsonObject(jsonField(channel, jsonResponseNames...)); response != nil {
+		c.Response.Offset = jsonAttr(response, "offset")
+		c.Response.Total = jsonAttr(response, "total")
+	}
+
+	for _, v := range jsonList(channel["item"]) {
+		if item := jsonObject(v); item != nil {
+			c.Item = append(c.Item, itemFromJson(item))
+		}
+	}
+
+	return
+}
+
+// itemFromJson converts a decoded JSON item to an Item.
+func itemFromJson(obj map[string]interface{}) Item {
+	var item Item
+	item.Title = jsonText(obj["title"])
+	item.Link = jsonText(obj["link"])
+	item.Comments = jsonText(obj["comments"])
+	item.PubDate = jsonText(obj["pubDate"])
+	item.Category = jsonText(obj["category"])
+	item.Description = jsonText(obj["description"])
+
+	item.Guid.Text = jsonText(obj["guid"], "guid")
+	if guid := jsonObject(obj["guid"]); guid != nil {
+		item.Guid.IsPermaLink = jsonAttr(guid, "isPermaLink")
+	}
+
+	if enclosure := jsonObject(obj["enclosure"]); enclosure != nil {
+		item.Enclosure.URL = jsonAttr(enclosure, "url")
+		item.Enclosure.Length = jsonAttr(enclosure, "length")
+		item.Enclosure.Type = jsonAttr(enclosure, "type")
+	}
+
+	item.Attr = appendJsonAttrs(item.Attr, jsonField(obj, jsonAttrNames...), "")
+	item.Attr = appendJsonAttrs(item.Attr, jsonField(obj, jsonTorznabNames...), TorznabNamespace)
+
+	return item
+}
+
+// appendJsonAttrs appends the decoded attributes to attrs. A namespace is recorded for torznab attributes so that
+// they can be told apart from newznab attributes.
+func appendJsonAttrs(attrs []Attr, v interface{}, namespace string) []Attr {
+	for _, a := range jsonList(v) {
+		if attr := jsonObject(a); attr != nil {
+			attrs = append(attrs, Attr{
+				XMLName: xml.Name{Space: namespace, Local: "attr"},
+				Name:    jsonAttr(attr, "name"),
+				Value:   jsonAttr(attr, "value"),
+			})
+		}
+	}
+	return attrs
+}
+
+// jsonField returns the value of the first of the names present in the object.
+func jsonField(obj map[string]interface{}, names ...string) interface{} {
+	for _, name := range names {
+		if v, ok := obj[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// jsonAttr returns the value of an XML attribute encoded as JSON. It may be wrapped in "@attributes", prefixed with "@"
+// or "_", or be a plain member of the object.
+func jsonAttr(obj map[string]interface{}, name string) string {
+	if attrs := jsonObject(obj["@attributes"]); attrs != nil {
+		if v, ok := attrs[name]; ok {
+			return jsonText(v)
+		}
+	}
+	return jsonText(jsonField(obj, "@"+name, "_"+name, name))
+}
+
+// jsonList returns the value as a list. Single values are wrapped, since most encoders don't distinguish between an
+// element that appears once and one that appears many times.
+func jsonList(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return t
+	}
+	return []interface{}{v}
+}
+
+// jsonObject returns the value as an object, or nil if it isn't one.
+func jsonObject(v interface{}) map[string]interface{} {
+	obj, _ := v.(map[string]interface{})
+	return obj
+}
+
+// jsonText returns the text content of a value. Objects are searched for the common names used for text content, plus
+// any extra names given. Empty objects, which encoders produce for empty elements, return an empty string.
+func jsonText(v interface{}, names ...string) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	case map[string]interface{}:
+		return jsonText(jsonField(t, append(jsonTextNames, names...)...))
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"encoding/json"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"testing"
+)
+
+// TestNewznabFromJson decodes the same feed as encoded by several server implementations and compares each to the
+// result of decoding the XML version.
+func TestNewznabFromJson(t *testing.T) {
+	original, err := ioutil.ReadFile("testdata/json/feed.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newznab, err := NewznabFromXml(original)
+	assert.With(t).That(err).IsNil()
+	expected := marshalForComparison(newznab)
+
+	for _, flavor := range []string{"newznab", "nzedb", "nntmux", "hydra"} {
+		data, err := ioutil.ReadFile("testdata/json/" + flavor + ".json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newznab, err := NewznabFromJson(data)
+		assert.With(t).That(err).IsNil()
+		assert.With(t).That(len(newznab.Channel.Item)).IsEqualTo(2)
+		assert.With(t).That(marshalForComparison(newznab)).IsEqualTo(expected)
+	}
+}
+
+func TestNewznabFromJsonWithoutChannel(t *testing.T) {
+	_, err := NewznabFromJson([]byte(`{"error": "nope"}`))
+	assert.With(t).That(err).IsNotNil()
+}
+
+func TestDecodeNewznabByContentType(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/json/nzedb.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newznab, err := decodeNewznab("application/json", data)
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(newznab.Channel.Response.Total).IsEqualTo("2")
+	assert.With(t).That(newznab.Channel.Item[0].Grabs()).IsEqualTo(12)
+}
+
+// marshalForComparison returns the JSON encoding of a Newznab struct, ignoring the namespace declarations, which have
+// no JSON equivalent, and isPermaLink, which PHP's json_encode drops from elements that also have text.
+func marshalForComparison(newznab Newznab) string {
+	newznab.Atom = ""
+	newznab.Newznab = ""
+	for i := range newznab.Channel.Item {
+		newznab.Channel.Item[i].Guid.IsPermaLink = ""
+	}
+
+	b, _ := json.MarshalIndent(newznab, "", "  ")
+	return string(b)
+}
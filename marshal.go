@@ -0,0 +1,182 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"encoding/xml"
+)
+
+// Namespaces declared by a Newznab feed.
+const (
+	AtomNamespace    = "http://www.w3.org/2005/Atom"
+	NewznabNamespace = "http://www.newznab.com/DTD/2010/feeds/attributes/"
+	TorznabNamespace = "http://torznab.com/schemas/2015/feed"
+)
+
+// The Newznab struct decodes elements regardless of their namespace prefix, so encoding/xml would write them back
+// without one. These structs mirror it with the prefixes spelled out in the element names.
+type (
+	xmlRss struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Atom    string     `xml:"xmlns:atom,attr"`
+		Newznab string     `xml:"xmlns:newznab,attr"`
+		Torznab string     `xml:"xmlns:torznab,attr,omitempty"`
+		Channel xmlChannel `xml:"channel"`
+	}
+
+	xmlChannel struct {
+		AtomLink    *xmlAtomLink `xml:"atom:link"`
+		Title       string       `xml:"title"`
+		Description string       `xml:"description"`
+		Link        string       `xml:"link"`
+		Language    string       `xml:"language,omitempty"`
+		WebMaster   string       `xml:"webMaster,omitempty"`
+		Category    string       `xml:"category"`
+		Image       *xmlImage    `xml:"image"`
+		Response    *xmlResponse `xml:"newznab:response"`
+		Item        []xmlItem    `xml:"item"`
+	}
+
+	xmlAtomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+		Type string `xml:"type,attr,omitempty"`
+	}
+
+	xmlImage struct {
+		URL         string `xml:"url"`
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description,omitempty"`
+	}
+
+	xmlResponse struct {
+		Offset string `xml:"offset,attr"`
+		Total  string `xml:"total,attr"`
+	}
+
+	xmlItem struct {
+		Title       string        `xml:"title"`
+		Guid        xmlGuid       `xml:"guid"`
+		Link        string        `xml:"link"`
+		Comments    string        `xml:"comments,omitempty"`
+		PubDate     string        `xml:"pubDate"`
+		Category    string        `xml:"category,omitempty"`
+		Description string        `xml:"description,omitempty"`
+		Enclosure   *xmlEnclosure `xml:"enclosure"`
+		Attr        []xmlAttr     `xml:"newznab:attr"`
+	}
+
+	xmlGuid struct {
+		Text        string `xml:",chardata"`
+		IsPermaLink string `xml:"isPermaLink,attr,omitempty"`
+	}
+
+	xmlEnclosure struct {
+		URL    string `xml:"url,attr"`
+		Length string `xml:"length,attr"`
+		Type   string `xml:"type,attr"`
+	}
+
+	// xmlAttr sets its XMLName to either newznab:attr or torznab:attr.
+	xmlAttr struct {
+		XMLName xml.Name
+		Name    string `xml:"name,attr"`
+		Value   string `xml:"value,attr"`
+	}
+)
+
+// MarshalXML encodes the feed as an RSS 2.0 document with the atom and newznab namespaces, so that it can be served to
+// clients that expect a Newznab server.
+func (n Newznab) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	rss := xmlRss{
+		Version: defaultString(n.Version, "2.0"),
+		Atom:    defaultString(n.Atom, AtomNamespace),
+		Newznab: defaultString(n.Newznab, NewznabNamespace),
+	}
+
+	c := n.Channel
+	rss.Channel = xmlChannel{
+		Title:       c.Title,
+		Description: c.Description,
+		Link:        c.Link.Text,
+		Language:    c.Language,
+		WebMaster:   c.WebMaster,
+		Category:    c.Category,
+		Item:        make([]xmlItem, len(c.Item)),
+	}
+
+	if len(c.Link.Href) > 0 {
+		rss.Channel.AtomLink = &xmlAtomLink{Href: c.Link.Href, Rel: c.Link.Rel, Type: c.Link.Type}
+	}
+
+	if img := c.Image; len(img.URL) > 0 || len(img.Title) > 0 || len(img.Link) > 0 {
+		rss.Channel.Image = &xmlImage{URL: img.URL, Title: img.Title, Link: img.Link, Description: img.Description}
+	}
+
+	if len(c.Response.Offset) > 0 || len(c.Response.Total) > 0 {
+		rss.Channel.Response = &xmlResponse{Offset: c.Response.Offset, Total: c.Response.Total}
+	}
+
+	for i, item := range c.Item {
+		x := xmlItem{
+			Title:       item.Title,
+			Guid:        xmlGuid{Text: item.Guid.Text, IsPermaLink: item.Guid.IsPermaLink},
+			Link:        item.Link,
+			Comments:    item.Comments,
+			PubDate:     item.PubDate,
+			Category:    item.Category,
+			Description: item.Description,
+			Attr:        make([]xmlAttr, len(item.Attr)),
+		}
+
+		if enc := item.Enclosure; len(enc.URL) > 0 {
+			x.Enclosure = &xmlEnclosure{URL: enc.URL, Length: enc.Length, Type: enc.Type}
+		}
+
+		for j, attr := range item.Attr {
+			name := "newznab:attr"
+			if attr.IsTorznab() {
+				name = "torznab:attr"
+				rss.Torznab = defaultString(n.Torznab, TorznabNamespace)
+			}
+			x.Attr[j] = xmlAttr{XMLName: xml.Name{Local: name}, Name: attr.Name, Value: attr.Value}
+		}
+
+		rss.Channel.Item[i] = x
+	}
+
+	return e.Encode(rss)
+}
+
+// ToXml encodes the feed as an indented RSS 2.0 document, including the XML declaration.
+func (n Newznab) ToXml() ([]byte, error) {
+	b, err := xml.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// defaultString returns s, or def if s is empty.
+func defaultString(s string, def string) string {
+	if len(s) == 0 {
+		return def
+	}
+	return s
+}
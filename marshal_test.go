@@ -0,0 +1,58 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"encoding/json"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestNewznabRoundTrip(t *testing.T) {
+	original, err := ioutil.ReadFile("testdata/search-results.xml")
+	if err != nil {
+		t.Error(err)
+	}
+
+	newznab, err := NewznabFromXml(original)
+	assert.With(t).That(err).IsNil()
+
+	data, err := newznab.ToXml()
+	assert.With(t).That(err).IsNil()
+
+	doc := string(data)
+	assert.With(t).That(strings.HasPrefix(doc, "<?xml")).IsEqualTo(true)
+	assert.With(t).That(strings.Contains(doc, `xmlns:newznab="`+NewznabNamespace+`"`)).IsEqualTo(true)
+	assert.With(t).That(strings.Contains(doc, `xmlns:atom="`+AtomNamespace+`"`)).IsEqualTo(true)
+	assert.With(t).That(strings.Contains(doc, `<atom:link href="https://example.com/api?`)).IsEqualTo(true)
+	assert.With(t).That(strings.Contains(doc, `<newznab:response offset="0" total="12">`)).IsEqualTo(true)
+	assert.With(t).That(strings.Count(doc, `<newznab:attr name="size"`)).IsEqualTo(12)
+
+	// Decoding the output must produce the same feed.
+	decoded, err := NewznabFromXml(data)
+	assert.With(t).That(err).IsNil()
+
+	expected, err := ioutil.ReadFile("testdata/search-results.json")
+	if err != nil {
+		t.Error(err)
+	}
+
+	actual, _ := json.MarshalIndent(decoded, "", "  ")
+	assert.With(t).That(string(actual)).IsEqualTo(string(expected))
+}
@@ -0,0 +1,155 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TvInfo contains the attributes that describe a TV episode.
+type TvInfo struct {
+	Title    string
+	RageId   int
+	TvdbId   int
+	TvmazeId int
+	ImdbId   string
+	Season   int
+	Episode  int
+	AirDate  time.Time
+}
+
+// MovieInfo contains the attributes that describe a movie.
+type MovieInfo struct {
+	ImdbId   string
+	Title    string
+	Year     int
+	Score    float64
+	Tagline  string
+	Plot     string
+	Director string
+	Actors   string
+	Genre    string
+	CoverUrl string
+}
+
+// MusicInfo contains the attributes that describe a music release.
+type MusicInfo struct {
+	Artist    string
+	Album     string
+	Publisher string
+	Year      int
+	Genre     string
+	Tracks    []string
+	CoverUrl  string
+}
+
+// BookInfo contains the attributes that describe an e-book.
+type BookInfo struct {
+	Title       string
+	Author      string
+	Publisher   string
+	PublishDate time.Time
+	Pages       int
+	Review      string
+	CoverUrl    string
+}
+
+// Book returns the e-book attributes of the item.
+func (i Item) Book() BookInfo {
+	publishDate, err := parseDate(i.AttrValue("publishdate"))
+	if err != nil {
+		publishDate, _ = time.Parse("2006-01-02", strings.TrimSpace(i.AttrValue("publishdate")))
+	}
+
+	return BookInfo{
+		Title:       i.AttrValue("booktitle"),
+		Author:      i.AttrValue("author"),
+		Publisher:   i.AttrValue("publisher"),
+		PublishDate: publishDate,
+		Pages:       i.intAttr("pages"),
+		Review:      i.AttrValue("review"),
+		CoverUrl:    i.AttrValue("coverurl"),
+	}
+}
+
+// Movie returns the movie attributes of the item.
+func (i Item) Movie() MovieInfo {
+	score, _ := strconv.ParseFloat(i.AttrValue("imdbscore"), 64)
+
+	return MovieInfo{
+		ImdbId:   i.AttrValue("imdb"),
+		Title:    i.AttrValue("imdbtitle"),
+		Year:     i.intAttr("imdbyear"),
+		Score:    score,
+		Tagline:  i.AttrValue("imdbtagline"),
+		Plot:     i.AttrValue("imdbplot"),
+		Director: i.AttrValue("imdbdirector"),
+		Actors:   i.AttrValue("imdbactors"),
+		Genre:    i.AttrValue("genre"),
+		CoverUrl: i.AttrValue("coverurl"),
+	}
+}
+
+// Music returns the music attributes of the item.
+func (i Item) Music() MusicInfo {
+	// Track listings are separated by pipes.
+	tracks := make([]string, 0)
+	for _, track := range strings.Split(i.AttrValue("tracks"), "|") {
+		if track = strings.TrimSpace(track); len(track) > 0 {
+			tracks = append(tracks, track)
+		}
+	}
+
+	return MusicInfo{
+		Artist:    i.AttrValue("artist"),
+		Album:     i.AttrValue("album"),
+		Publisher: i.AttrValue("publisher"),
+		Year:      i.intAttr("year"),
+		Genre:     i.AttrValue("genre"),
+		Tracks:    tracks,
+		CoverUrl:  i.AttrValue("coverurl"),
+	}
+}
+
+// Tv returns the TV attributes of the item.
+func (i Item) Tv() TvInfo {
+	airDate, _ := parseDate(i.AttrValue("tvairdate"))
+
+	return TvInfo{
+		Title:    i.AttrValue("tvtitle"),
+		RageId:   i.intAttr("rageid"),
+		TvdbId:   i.intAttr("tvdbid"),
+		TvmazeId: i.intAttr("tvmazeid"),
+		ImdbId:   i.AttrValue("imdb"),
+		Season:   digits(i.AttrValue("season")),
+		Episode:  digits(i.AttrValue("episode")),
+		AirDate:  airDate,
+	}
+}
+
+// digits returns the number in a value such as "S02" or "E22", ignoring any other characters.
+func digits(s string) int {
+	n, _ := strconv.Atoi(strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s))
+	return n
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"encoding/xml"
 	"golang.org/x/net/html/charset"
+	"mime"
+	"strings"
 )
 
 // Newznab represents the RSS feed returned from a query. The struct was generated by pasting
@@ -30,6 +32,7 @@ type Newznab struct {
 	Version string   `xml:"version,attr"`
 	Atom    string   `xml:"atom,attr"`
 	Newznab string   `xml:"newznab,attr"`
+	Torznab string   `xml:"torznab,attr" json:",omitempty"`
 	Channel struct {
 		Text string `xml:",chardata" json:"-"`
 		Link struct {
@@ -55,30 +58,7 @@ type Newznab struct {
 			Offset string `xml:"offset,attr"`
 			Total  string `xml:"total,attr"`
 		} `xml:"response"`
-		Item []struct {
-			Text  string `xml:",chardata" json:"-"`
-			Title string `xml:"title"`
-			Guid  struct {
-				Text        string `xml:",chardata"`
-				IsPermaLink string `xml:"isPermaLink,attr"`
-			} `xml:"guid"`
-			Link        string `xml:"link"`
-			Comments    string `xml:"comments"`
-			PubDate     string `xml:"pubDate"`
-			Category    string `xml:"category"`
-			Description string `xml:"description"`
-			Enclosure   struct {
-				Text   string `xml:",chardata" json:"-"`
-				URL    string `xml:"url,attr"`
-				Length string `xml:"length,attr"`
-				Type   string `xml:"type,attr"`
-			} `xml:"enclosure"`
-			Attr []struct {
-				Text  string `xml:",chardata" json:"-"`
-				Name  string `xml:"name,attr"`
-				Value string `xml:"value,attr"`
-			} `xml:"attr"`
-		} `xml:"item"`
+		Item []Item `xml:"item"`
 	} `xml:"channel"`
 }
 
@@ -92,3 +72,33 @@ func NewznabFromXml(data []byte) (newznab Newznab, err error) {
 	err = decoder.Decode(&newznab)
 	return
 }
+
+// decodeNewznab decodes search results using the format given by the Content-Type header. The body is inspected when
+// the header is missing or too generic to be useful, which is common with smaller indexers.
+func decodeNewznab(contentType string, body []byte) (*Newznab, error) {
+	decode := NewznabFromXml
+	if isJson(contentType, body) {
+		decode = NewznabFromJson
+	}
+
+	newznab, err := decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return &newznab, nil
+}
+
+// isJson reports whether a response is in JSON format.
+func isJson(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if strings.HasSuffix(mediaType, "json") {
+			return true
+		}
+		if strings.HasSuffix(mediaType, "xml") {
+			return false
+		}
+	}
+
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
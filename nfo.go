@@ -0,0 +1,94 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"context"
+	"golang.org/x/text/encoding/charmap"
+	"unicode/utf8"
+)
+
+// GetNfo returns the NFO file of a release, identified by its GUID. Pass Raw() to have the server send the file
+// itself instead of wrapping it in a feed; either response is handled.
+func (c *Client) GetNfo(guid string, params ...Param) (string, error) {
+	return c.GetNfoContext(context.Background(), guid, params...)
+}
+
+// GetNfoContext returns the NFO file of a release using the provided context.
+func (c *Client) GetNfoContext(ctx context.Context, guid string, params ...Param) (string, error) {
+	p := append(params, Apikey(c.key), nzbid(guid), Type("getnfo"))
+	res, err := c.call(ctx, p...)
+	if err != nil {
+		return "", err
+	}
+
+	// The NFO is either the description of the only item in a feed, or the body of the response.
+	if isFeed(res.header.Get("Content-Type"), res.body) {
+		feed, err := decodeNewznab(res.header.Get("Content-Type"), res.body)
+		if err != nil {
+			return "", err
+		}
+		if len(feed.Channel.Item) == 0 {
+			return "", ErrNoSuchItem
+		}
+		return feed.Channel.Item[0].Description, nil
+	}
+
+	return DecodeNfo(res.body), nil
+}
+
+// GetNfo returns the NFO file of a release, identified by its GUID.
+func GetNfo(url string, key string, guid string, params ...Param) (string, error) {
+	return newClient(url, key).GetNfo(guid, params...)
+}
+
+// GetNfoContext returns the NFO file of a release using the provided context.
+func GetNfoContext(ctx context.Context, url string, key string, guid string, params ...Param) (string, error) {
+	return newClient(url, key).GetNfoContext(ctx, guid, params...)
+}
+
+// DecodeNfo converts the contents of an NFO file to a UTF-8 string. NFO files are traditionally encoded with code page
+// 437 so that their box-drawing characters display correctly. Content that is already valid UTF-8 is returned as-is.
+func DecodeNfo(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	decoded, err := charmap.CodePage437.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+// isFeed reports whether a response is an RSS feed, in either XML or JSON format, rather than a plain file.
+func isFeed(contentType string, body []byte) bool {
+	if isJson(contentType, body) {
+		return true
+	}
+
+	// Only look at the start of the body, past any XML declaration.
+	head := bytes.TrimSpace(body)
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if bytes.HasPrefix(head, []byte("<?xml")) {
+		return bytes.Contains(head, []byte("<rss"))
+	}
+	return bytes.HasPrefix(head, []byte("<rss"))
+}
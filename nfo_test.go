@@ -0,0 +1,58 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetNfoRaw(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/plain")
+		// "╔══╗ Release" encoded with code page 437.
+		_, _ = w.Write([]byte{0xc9, 0xcd, 0xcd, 0xbb, ' ', 'R', 'e', 'l', 'e', 'a', 's', 'e'})
+	}))
+	defer server.Close()
+
+	nfo, err := GetNfo(server.URL, "key", "1a2b3c", Raw())
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(nfo).IsEqualTo("╔══╗ Release")
+	assert.With(t).That(query).IsEqualTo("apikey=key&id=1a2b3c&raw=1&t=getnfo")
+}
+
+func TestGetNfoFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><item><title>NFO</title><description>╔══╗ Release</description></item></channel></rss>`))
+	}))
+	defer server.Close()
+
+	nfo, err := GetNfo(server.URL, "key", "1a2b3c")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(nfo).IsEqualTo("╔══╗ Release")
+}
+
+func TestDecodeNfoUtf8(t *testing.T) {
+	assert.With(t).That(DecodeNfo([]byte("Plain ASCII"))).IsEqualTo("Plain ASCII")
+	assert.With(t).That(DecodeNfo([]byte("Ünïcode"))).IsEqualTo("Ünïcode")
+}
@@ -0,0 +1,237 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Pager walks through every page of results for a query by advancing the offset after each request.
+//
+//	p := c.NewPager("tvsearch", []newznab.Param{newznab.Query("The Office")}, newznab.MaxItems(500))
+//	for p.More() {
+//		page, err := p.Next(ctx)
+//		...
+//	}
+type Pager struct {
+	client   *Client
+	t        string
+	params   []Param
+	limit    int
+	offset   int
+	maxItems int
+	maxPages int
+	items    int
+	pages    int
+	done     bool
+
+	// size is the page size, resolved once by the first call to Next.
+	size  int
+	sized bool
+
+	// first identifies the first item of the previous page, to recognise a server that sends the same page again.
+	first string
+}
+
+// PagerOption configures a Pager.
+type PagerOption func(*Pager)
+
+// MaxItems returns a PagerOption that stops the Pager once n items have been returned.
+func MaxItems(n int) PagerOption {
+	return func(p *Pager) {
+		p.maxItems = n
+	}
+}
+
+// MaxPages returns a PagerOption that stops the Pager once n pages have been requested.
+func MaxPages(n int) PagerOption {
+	return func(p *Pager) {
+		p.maxPages = n
+	}
+}
+
+// NewPager returns a Pager for a search of type "t", such as "search" or "tvsearch". An Offset in the parameters is
+// used as the starting point. A Limit sets the page size, otherwise the server's maximum is used.
+func (c *Client) NewPager(t string, params []Param, opts ...PagerOption) *Pager {
+	p := &Pager{
+		client: c,
+		t:      t,
+		params: make([]Param, 0, len(params)),
+	}
+
+	for _, param := range params {
+		switch param.Name {
+		case "limit":
+			p.limit, _ = strconv.Atoi(param.Value)
+		case "offset":
+			p.offset, _ = strconv.Atoi(param.Value)
+		default:
+			p.params = append(p.params, param)
+		}
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// More reports whether another call to Next may return results.
+func (p *Pager) More() bool {
+	return !p.done
+}
+
+// Next requests the next page of results. It returns nil once there are no more results, including when the server
+// sends a page it has already sent.
+func (p *Pager) Next(ctx context.Context) (*Newznab, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	if !p.sized {
+		p.size = p.pageSize(ctx)
+		p.sized = true
+	}
+
+	limit := p.size
+	params := make([]Param, 0, len(p.params)+2)
+	params = append(params, p.params...)
+	params = append(params, Offset(p.offset))
+	if limit > 0 {
+		params = append(params, Limit(limit))
+	}
+
+	page, err := p.client.searchParsed(ctx, p.t, params...)
+	if err != nil {
+		return nil, err
+	}
+	p.pages++
+
+	// A server that ignores the offset sends the same page again. Stop rather than return it, which would otherwise
+	// repeat forever when neither the page size nor the total is known.
+	reported, err := strconv.Atoi(page.Channel.Response.Offset)
+	if err != nil {
+		reported = -1
+	}
+	if p.repeated(page, reported) {
+		p.done = true
+		return nil, nil
+	}
+
+	count := len(page.Channel.Item)
+	if p.maxItems > 0 && p.items+count >= p.maxItems {
+		count = p.maxItems - p.items
+		page.Channel.Item = page.Channel.Item[:count]
+		p.done = true
+	}
+	p.items += count
+
+	// Use the offset reported by the server if it's ahead of the one requested, but never go backwards.
+	next := p.offset
+	if reported > next {
+		next = reported
+	}
+	p.offset = next + len(page.Channel.Item)
+
+	total, err := strconv.Atoi(page.Channel.Response.Total)
+	switch {
+	case count == 0:
+		p.done = true
+	case limit > 0 && count < limit:
+		// A short page is the last page.
+		p.done = true
+	case err == nil && total > 0 && p.offset >= total:
+		p.done = true
+	case p.maxPages > 0 && p.pages >= p.maxPages:
+		p.done = true
+	}
+
+	return page, nil
+}
+
+// repeated reports whether a page is one the server has already sent, and remembers its first item. Pages are
+// compared by the GUID, or else the title, of their first item. The reported offset is only trusted when the items
+// can't be told apart, since some servers honour the offset but always report zero.
+func (p *Pager) repeated(page *Newznab, reported int) bool {
+	if p.pages <= 1 || len(page.Channel.Item) == 0 {
+		p.first = firstItemKey(page)
+		return false
+	}
+
+	key := firstItemKey(page)
+	if len(key) == 0 {
+		return reported >= 0 && reported < p.offset
+	}
+	if key == p.first {
+		return true
+	}
+
+	p.first = key
+	return false
+}
+
+// firstItemKey identifies the first item of a page by its GUID or, failing that, its title.
+func firstItemKey(page *Newznab) string {
+	if len(page.Channel.Item) == 0 {
+		return ""
+	}
+
+	item := page.Channel.Item[0]
+	if guid := strings.TrimSpace(item.Guid.Text); len(guid) > 0 {
+		return guid
+	}
+	return strings.TrimSpace(item.Title)
+}
+
+// pageSize returns the number of results to request, capped at the server's maximum. If the capabilities can't be
+// retrieved the requested limit is used as-is and the server applies its own default. It's called once per Pager so
+// that a failing caps request isn't repeated for every page.
+func (p *Pager) pageSize(ctx context.Context) int {
+	caps, err := p.client.Capabilities(ctx)
+	if err != nil || caps.Limits.Max <= 0 {
+		return p.limit
+	}
+
+	if p.limit <= 0 || p.limit > caps.Limits.Max {
+		return caps.Limits.Max
+	}
+	return p.limit
+}
+
+// SearchAll runs a search of type "t" and returns the items from every page combined into the first page.
+func (c *Client) SearchAll(ctx context.Context, t string, params []Param, opts ...PagerOption) (*Newznab, error) {
+	var all *Newznab
+
+	p := c.NewPager(t, params, opts...)
+	for p.More() {
+		page, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if all == nil {
+			all = page
+		} else if page != nil {
+			all.Channel.Item = append(all.Channel.Item, page.Channel.Item...)
+		}
+	}
+
+	return all, nil
+}
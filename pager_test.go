@@ -0,0 +1,212 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"fmt"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pagedServer serves caps with a maximum of 100 results and a search with "total" results.
+func pagedServer(total int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("t") == "caps" {
+			_, _ = w.Write([]byte(`<caps><limits max="100" default="50"/></caps>`))
+			return
+		}
+		*requests++
+
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		var b strings.Builder
+		fmt.Fprintf(&b, `<rss><channel><newznab:response offset="%d" total="%d"/>`, offset, total)
+		for i := offset; i < offset+limit && i < total; i++ {
+			fmt.Fprintf(&b, "<item><title>Item %d</title></item>", i)
+		}
+		b.WriteString("</channel></rss>")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+}
+
+func TestSearchAll(t *testing.T) {
+	requests := 0
+	server := pagedServer(250, &requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	res, err := c.SearchAll(context.Background(), "search", []Param{Query("test")})
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(3)
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(250)
+	assert.With(t).That(res.Channel.Item[249].Title).IsEqualTo("Item 249")
+}
+
+func TestPagerMaxItems(t *testing.T) {
+	requests := 0
+	server := pagedServer(1000, &requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	p := c.NewPager("search", []Param{Query("test"), Limit(40), Offset(10)}, MaxItems(90))
+
+	items := 0
+	for p.More() {
+		page, err := p.Next(context.Background())
+		assert.With(t).That(err).IsNil()
+		items += len(page.Channel.Item)
+	}
+
+	assert.With(t).That(requests).IsEqualTo(3)
+	assert.With(t).That(items).IsEqualTo(90)
+}
+
+func TestPagerMaxPages(t *testing.T) {
+	requests := 0
+	server := pagedServer(1000, &requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	res, err := c.SearchAll(context.Background(), "search", nil, MaxPages(2))
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(2)
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(200)
+}
+
+func TestPagerIgnoredOffset(t *testing.T) {
+	offsets := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("t") == "caps" {
+			_, _ = w.Write([]byte(`<caps><limits max="100" default="50"/></caps>`))
+			return
+		}
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+
+		// Always return the first page, whatever offset was requested.
+		var b strings.Builder
+		b.WriteString(`<rss><channel><newznab:response offset="0"/>`)
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(&b, "<item><title>Item %d</title></item>", i)
+		}
+		b.WriteString("</channel></rss>")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	res, err := c.SearchAll(context.Background(), "search", nil)
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(strings.Join(offsets, ",")).IsEqualTo("0,100")
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(100)
+}
+
+func TestPagerCapsFailure(t *testing.T) {
+	caps := 0
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("t") == "caps" {
+			caps++
+			_, _ = w.Write([]byte(`<error code="100" description="Incorrect user credentials"/>`))
+			return
+		}
+		requests++
+
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		fmt.Fprintf(w, `<rss><channel><newznab:response offset="%d" total="250"/><item><title>Item %d</title></item></channel></rss>`, offset, offset)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	_, err := c.SearchAll(context.Background(), "search", nil, MaxPages(5))
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(5)
+	assert.With(t).That(caps).IsEqualTo(1)
+}
+
+func TestPagerIgnoredOffsetWithoutLimits(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("t") == "caps" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+
+		// No caps, no offset and no total: only the repeated items show that the offset is ignored.
+		var b strings.Builder
+		b.WriteString(`<rss><channel>`)
+		for i := 0; i < 100; i++ {
+			fmt.Fprintf(&b, "<item><title>Item %d</title><guid>guid-%d</guid></item>", i, i)
+		}
+		b.WriteString("</channel></rss>")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	res, err := c.SearchAll(ctx, "search", nil)
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(2)
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(100)
+}
+
+func TestPagerMisreportedOffset(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("t") == "caps" {
+			_, _ = w.Write([]byte(`<caps><limits max="100" default="50"/></caps>`))
+			return
+		}
+		requests++
+
+		// The offset is honoured but always reported as zero.
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		var b strings.Builder
+		b.WriteString(`<rss><channel><newznab:response offset="0" total="250"/>`)
+		for i := offset; i < offset+100 && i < 250; i++ {
+			fmt.Fprintf(&b, "<item><title>Item %d</title><guid>guid-%d</guid></item>", i, i)
+		}
+		b.WriteString("</channel></rss>")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	res, err := c.SearchAll(context.Background(), "search", nil)
+
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(3)
+	assert.With(t).That(len(res.Channel.Item)).IsEqualTo(250)
+	assert.With(t).That(res.Channel.Item[249].Title).IsEqualTo("Item 249")
+}
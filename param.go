@@ -52,6 +52,15 @@ func Artist(a string) Param {
 	}
 }
 
+// Attrs returns a Param that limits the attributes returned with each result to the given names. Torznab servers use
+// this to return attributes such as "seeders" and "infohash" without the cost of "extended" results.
+func Attrs(names ...string) Param {
+	return Param{
+		Name:  "attrs",
+		Value: strings.Join(names, ","),
+	}
+}
+
 // Author returns a Param that restricts the search of e-books to a specific author.
 func Author(a string) Param {
 	return Param{
@@ -73,6 +82,22 @@ func Categories(cats ...Category) Param {
 	}
 }
 
+// Delete returns a Param that directs an RSS feed of the user's cart to remove each release once it is listed.
+func Delete() Param {
+	return Param{
+		Name:  "del",
+		Value: "1",
+	}
+}
+
+// Download returns a Param that directs an RSS feed to link directly to each NZB file rather than its details page.
+func Download() Param {
+	return Param{
+		Name:  "dl",
+		Value: "1",
+	}
+}
+
 // Episode returns a Param that restricts the search of TV shows to a specific episode
 func Episode(e int) Param {
 	return Param{
@@ -97,7 +122,8 @@ func ImdbId(i int) Param {
 	}
 }
 
-// Json returns a Param that directs the service to produce JSON formatted output.
+// Json returns a Param that directs the service to produce JSON formatted output. The output can be decoded with
+// NewznabFromJson.
 func Json() Param {
 	return Param{
 		Name:  "o",
@@ -129,9 +155,18 @@ func MaxAge(m int) Param {
 	}
 }
 
+// Num returns a Param that defines the number of releases to include in an RSS feed.
+func Num(n int) Param {
+	return Param{
+		Name:  "num",
+		Value: strconv.Itoa(n),
+	}
+}
+
 // Offset returns a Param that directs the service to return results starting a the specified offset. This is useful
 // when a query would return more results than the service is able to provide in a single response. The consumer of
-// this library can retrieve the next batch by re-running the same query, but with an offset.
+// this library can retrieve the next batch by re-running the same query, but with an offset, or use a Pager to do so
+// automatically.
 func Offset(o int) Param {
 	return Param{
 		Name:  "offset",
@@ -147,6 +182,22 @@ func Query(q string) Param {
 	}
 }
 
+// Raw returns a Param that directs the service to send a file, such as an NFO, as-is rather than wrapped in a feed.
+func Raw() Param {
+	return Param{
+		Name:  "raw",
+		Value: "1",
+	}
+}
+
+// RssKey returns a Param that defines the key used to access an RSS feed. It is normally the API key.
+func RssKey(r string) Param {
+	return Param{
+		Name:  "r",
+		Value: r,
+	}
+}
+
 // Season returns a Param that restricts the search of TV shows to a specific season
 func Season(s int) Param {
 	return Param{
@@ -171,6 +222,14 @@ func Track(t string) Param {
 	}
 }
 
+// TvdbId returns a Param that contains the TheTVDB ID of the TV show to search for.
+func TvdbId(i int) Param {
+	return Param{
+		Name:  "tvdbid",
+		Value: strconv.Itoa(i),
+	}
+}
+
 // Type returns a Param that defines the type of request being made.
 func Type(t string) Param {
 	return Param{
@@ -179,6 +238,14 @@ func Type(t string) Param {
 	}
 }
 
+// UserId returns a Param that identifies the user an RSS feed belongs to.
+func UserId(i string) Param {
+	return Param{
+		Name:  "i",
+		Value: i,
+	}
+}
+
 // Xml returns a Param that directs the service to produce XML formatted output.
 func Xml() Param {
 	return Param{
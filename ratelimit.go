@@ -0,0 +1,257 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit sets how quickly and how often a Client may send requests. Servers ban keys that go over their quotas, so
+// the Client checks each request against the limits before it's sent. Zero values disable the matching limit.
+type RateLimit struct {
+	// RequestsPerSecond is the steady rate requests are sent at. Burst requests may be sent at once after a quiet
+	// period; it defaults to the rate rounded up.
+	RequestsPerSecond float64
+	Burst             int
+
+	// ApiHits is the number of requests allowed in each rolling ApiWindow, which defaults to a day.
+	ApiHits   int
+	ApiWindow time.Duration
+
+	// Grabs is the number of NZB or torrent downloads allowed in each rolling GrabWindow, which defaults to a day.
+	Grabs      int
+	GrabWindow time.Duration
+
+	// FailFast returns a *LimitError instead of waiting until the request is allowed.
+	FailFast bool
+}
+
+// LimitError is returned when a request would go over one of the Client's limits. It matches ErrDownloadLimitReached
+// when the limit is on grabs and ErrRequestLimitReached otherwise, so client and server limits can be handled the
+// same way.
+type LimitError struct {
+	// Limit is the limit that would be exceeded: "requests", "api" or "grabs".
+	Limit string
+
+	// Wait is how long until the request would be allowed.
+	Wait time.Duration
+}
+
+// Quota is a snapshot of the requests and grabs counted against a Client's limits.
+type Quota struct {
+	ApiHits   int
+	ApiLimit  int
+	Grabs     int
+	GrabLimit int
+}
+
+// limiter enforces a RateLimit using a token bucket for the request rate and rolling counters for the quotas.
+type limiter struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	hits   []time.Time
+	grabs  []time.Time
+}
+
+// WithRateLimit returns an Option that limits how quickly and how often the Client sends requests.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter = newLimiter(limit)
+	}
+}
+
+// Error describes the limit that would be exceeded.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("newznab: %s limit reached, retry in %s", e.Limit, e.Wait.Round(time.Second))
+}
+
+// Is reports whether target is the APIError a server would send for the same limit.
+func (e *LimitError) Is(target error) bool {
+	if e.Limit == "grabs" {
+		return ErrDownloadLimitReached.Is(target)
+	}
+	return ErrRequestLimitReached.Is(target)
+}
+
+// Quota returns the requests and grabs counted in the current windows of the Client's limits.
+func (c *Client) Quota() Quota {
+	l := c.rateLimiter()
+	if l == nil {
+		return Quota{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(time.Now())
+	return Quota{
+		ApiHits:   len(l.hits),
+		ApiLimit:  l.limit.ApiHits,
+		Grabs:     len(l.grabs),
+		GrabLimit: l.limit.Grabs,
+	}
+}
+
+// SeedRateLimit sets the Client's quotas from the account's usage and limits returned by a "t=user" request. The
+// requests and grabs the server has already counted are treated as if they were made just now. A Client without a
+// RateLimit gets one with only the quotas set.
+func (c *Client) SeedRateLimit(ctx context.Context) error {
+	user, err := c.GetUserContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.limiterMu.Lock()
+	if c.limiter == nil {
+		c.limiter = newLimiter(RateLimit{})
+	}
+	l := c.limiter
+	c.limiterMu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if user.ApiLimit > 0 {
+		l.limit.ApiHits = user.ApiLimit
+		l.hits = seed(user.ApiHits, now)
+	}
+	if user.GrabLimit > 0 {
+		l.limit.Grabs = user.GrabLimit
+		l.grabs = seed(user.Grabs, now)
+	}
+
+	return nil
+}
+
+// wait blocks until a request is allowed by the Client's limits and counts it. Grabs also count against the grab
+// quota.
+func (c *Client) wait(ctx context.Context, grab bool) error {
+	l := c.rateLimiter()
+	if l == nil {
+		return nil
+	}
+
+	for {
+		err := l.reserve(grab)
+		if err == nil {
+			return nil
+		}
+		if l.limit.FailFast {
+			return err
+		}
+
+		timer := time.NewTimer(err.Wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimiter returns the Client's limiter, or nil if it has none.
+func (c *Client) rateLimiter() *limiter {
+	c.limiterMu.Lock()
+	defer c.limiterMu.Unlock()
+	return c.limiter
+}
+
+func newLimiter(limit RateLimit) *limiter {
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Ceil(limit.RequestsPerSecond))
+	}
+	if limit.ApiWindow <= 0 {
+		limit.ApiWindow = 24 * time.Hour
+	}
+	if limit.GrabWindow <= 0 {
+		limit.GrabWindow = 24 * time.Hour
+	}
+
+	return &limiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve counts a request if the limits allow it, otherwise it returns the limit that would be exceeded.
+func (l *limiter) reserve(grab bool) *LimitError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	if l.limit.ApiHits > 0 && len(l.hits) >= l.limit.ApiHits {
+		return &LimitError{Limit: "api", Wait: l.hits[0].Add(l.limit.ApiWindow).Sub(now)}
+	}
+	if grab && l.limit.Grabs > 0 && len(l.grabs) >= l.limit.Grabs {
+		return &LimitError{Limit: "grabs", Wait: l.grabs[0].Add(l.limit.GrabWindow).Sub(now)}
+	}
+
+	if l.limit.RequestsPerSecond > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.limit.RequestsPerSecond
+		if l.tokens > float64(l.limit.Burst) {
+			l.tokens = float64(l.limit.Burst)
+		}
+		l.last = now
+
+		if l.tokens < 1 {
+			wait := time.Duration((1 - l.tokens) / l.limit.RequestsPerSecond * float64(time.Second))
+			return &LimitError{Limit: "requests", Wait: wait}
+		}
+		l.tokens--
+	}
+
+	l.hits = append(l.hits, now)
+	if grab {
+		l.grabs = append(l.grabs, now)
+	}
+	return nil
+}
+
+// prune forgets the requests and grabs that have left their windows.
+func (l *limiter) prune(now time.Time) {
+	l.hits = expire(l.hits, now.Add(-l.limit.ApiWindow))
+	l.grabs = expire(l.grabs, now.Add(-l.limit.GrabWindow))
+}
+
+// expire removes the times at or before the cutoff from a sorted slice.
+func expire(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+// seed returns n copies of the given time.
+func seed(n int, t time.Time) []time.Time {
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = t
+	}
+	return times
+}
@@ -0,0 +1,152 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"errors"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func rateLimitServer(requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		switch r.URL.Query().Get("t") {
+		case "user":
+			_, _ = w.Write([]byte(`<user username="user" grabs="5" downloadlimit="5" apirequests="10" apilimit="100"/>`))
+		case "get":
+			_, _ = w.Write([]byte(`<nzb/>`))
+		default:
+			_, _ = w.Write([]byte(`<rss/>`))
+		}
+	}))
+}
+
+func TestRateLimitApiHits(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRateLimit(RateLimit{ApiHits: 2, FailFast: true}))
+	_, err := c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+	_, err = c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+
+	_, err = c.Search(Query("test"))
+	var limitErr *LimitError
+	assert.With(t).That(errors.As(err, &limitErr)).IsEqualTo(true)
+	assert.With(t).That(limitErr.Limit).IsEqualTo("api")
+	assert.With(t).That(limitErr.Wait > 23*time.Hour).IsEqualTo(true)
+	assert.With(t).That(errors.Is(err, ErrRequestLimitReached)).IsEqualTo(true)
+	assert.With(t).That(requests).IsEqualTo(2)
+
+	q := c.Quota()
+	assert.With(t).That(q.ApiHits).IsEqualTo(2)
+	assert.With(t).That(q.ApiLimit).IsEqualTo(2)
+}
+
+func TestRateLimitGrabs(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRateLimit(RateLimit{Grabs: 1, FailFast: true}))
+	_, err := c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(err).IsNil()
+
+	_, err = c.GetTorrent("1a2b3c")
+	assert.With(t).That(errors.Is(err, ErrDownloadLimitReached)).IsEqualTo(true)
+
+	// Searches don't count as grabs.
+	_, err = c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(requests).IsEqualTo(2)
+}
+
+func TestRateLimitRequestsPerSecond(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRateLimit(RateLimit{RequestsPerSecond: 20, Burst: 1}))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.Search(Query("test"))
+		assert.With(t).That(err).IsNil()
+	}
+	assert.With(t).That(time.Since(start) >= 90*time.Millisecond).IsEqualTo(true)
+	assert.With(t).That(requests).IsEqualTo(3)
+}
+
+func TestRateLimitBlocksUntilCanceled(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRateLimit(RateLimit{ApiHits: 1}))
+	_, err := c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = c.SearchContext(ctx, Query("test"))
+	assert.With(t).That(errors.Is(err, context.DeadlineExceeded)).IsEqualTo(true)
+	assert.With(t).That(requests).IsEqualTo(1)
+}
+
+func TestSeedRateLimit(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRateLimit(RateLimit{FailFast: true}))
+	assert.With(t).That(c.SeedRateLimit(context.Background())).IsNil()
+
+	q := c.Quota()
+	assert.With(t).That(q.ApiHits).IsEqualTo(10)
+	assert.With(t).That(q.ApiLimit).IsEqualTo(100)
+	assert.With(t).That(q.Grabs).IsEqualTo(5)
+	assert.With(t).That(q.GrabLimit).IsEqualTo(5)
+
+	_, err := c.GetNzbRaw("1a2b3c")
+	assert.With(t).That(errors.Is(err, ErrDownloadLimitReached)).IsEqualTo(true)
+	assert.With(t).That(requests).IsEqualTo(1)
+}
+
+func TestSeedRateLimitConcurrentQuota(t *testing.T) {
+	requests := 0
+	server := rateLimitServer(&requests)
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = c.Quota()
+		}
+	}()
+	assert.With(t).That(c.SeedRateLimit(context.Background())).IsNil()
+	<-done
+
+	assert.With(t).That(c.Quota().ApiLimit).IsEqualTo(100)
+}
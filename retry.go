@@ -0,0 +1,164 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries requests that fail for reasons that are likely to be temporary: dropped
+// connections, timeouts, 5xx and 429 responses, and the Newznab errors 429, 500 and 900. Requests that change the
+// server's state, such as adding a comment, registering or reading a cart feed with Delete, are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request is tried, including the first. Values below 2 disable
+	// retries.
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry. It doubles after each attempt.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the wait between attempts. A Retry-After header from the server is honored even when it's longer.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy tries each request up to three times, waiting about half a second and then a second between
+// attempts.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// nonIdempotent are the request types that must never be sent twice. Requests with "del=1" are never sent twice
+// either.
+var nonIdempotent = map[string]bool{
+	"cartadd":    true,
+	"cartdel":    true,
+	"commentadd": true,
+	"register":   true,
+}
+
+// WithRetry returns an Option that retries transient failures according to the policy. By default requests are tried
+// only once.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// retry runs attempt until it succeeds, the error isn't worth retrying, or the policy's attempts are used up. The
+// attempt returns the response headers, if any, so that a Retry-After header can be honored.
+func (c *Client) retry(ctx context.Context, u *url.URL, attempt func() (http.Header, error)) error {
+	for n := 1; ; n++ {
+		header, err := attempt()
+		if err == nil || n >= c.retryPolicy.MaxAttempts || !idempotent(u) || !isTransient(err) {
+			return err
+		}
+
+		timer := time.NewTimer(c.retryPolicy.delay(n, header))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// idempotent reports whether a request can safely be sent more than once.
+func idempotent(u *url.URL) bool {
+	q := u.Query()
+	return !nonIdempotent[q.Get("t")] && q.Get("del") != "1"
+}
+
+// delay returns how long to wait after the given attempt. The server's Retry-After header wins; otherwise the delay
+// grows exponentially, with jitter so that many clients don't retry in lockstep.
+func (p RetryPolicy) delay(attempt int, header http.Header) time.Duration {
+	if d, ok := retryAfter(header); ok {
+		return d
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Wait somewhere between half and all of the delay.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter parses a Retry-After header given in either seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// isTransient reports whether a request that failed with err may succeed if it's tried again.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Is(ErrRequestLimitReached) || apiErr.Is(ErrUnknown)
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
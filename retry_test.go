@@ -0,0 +1,138 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"errors"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetryTransient(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			_, _ = w.Write([]byte(`<error code="429" description="Request limit reached"/>`))
+		default:
+			_, _ = w.Write([]byte("<rss/>"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRetry(testRetryPolicy))
+	res, err := c.Search(Query("test"))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(res).IsEqualTo("<rss/>")
+	assert.With(t).That(attempts).IsEqualTo(3)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRetry(testRetryPolicy))
+	_, err := c.Search(Query("test"))
+
+	var statusErr *StatusError
+	assert.With(t).That(errors.As(err, &statusErr)).IsEqualTo(true)
+	assert.With(t).That(statusErr.StatusCode).IsEqualTo(http.StatusBadGateway)
+	assert.With(t).That(err.Error()).IsEqualTo("502 Bad Gateway")
+	assert.With(t).That(attempts).IsEqualTo(3)
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		_, _ = w.Write([]byte(`<error code="100" description="Incorrect user credentials"/>`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRetry(testRetryPolicy))
+	_, err := c.Search(Query("test"))
+	assert.With(t).That(errors.Is(err, ErrIncorrectCredentials)).IsEqualTo(true)
+	assert.With(t).That(attempts).IsEqualTo(1)
+}
+
+func TestRetrySkipsNonIdempotent(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		_, _ = w.Write([]byte(`<error code="900" description="Unknown error"/>`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithRetry(testRetryPolicy))
+	_, err := c.AddComment("1a2b3c", "Thanks!")
+	assert.With(t).That(errors.Is(err, ErrUnknown)).IsEqualTo(true)
+	assert.With(t).That(attempts).IsEqualTo(1)
+
+	assert.With(t).That(c.AddToCart("1a2b3c")).IsNotNil()
+	assert.With(t).That(attempts).IsEqualTo(2)
+
+	_, err = c.Feed(MyCart, "38759", Delete())
+	assert.With(t).That(err).IsNotNil()
+	assert.With(t).That(attempts).IsEqualTo(3)
+
+	// Reads of the same item are retried.
+	_, err = c.GetComments("1a2b3c")
+	assert.With(t).That(errors.Is(err, ErrUnknown)).IsEqualTo(true)
+	assert.With(t).That(attempts).IsEqualTo(6)
+}
+
+func TestRetryErrorCodes(t *testing.T) {
+	assert.With(t).That(isTransient(ErrTooManyRequests)).IsEqualTo(true)
+	assert.With(t).That(isTransient(ErrRequestLimitReached)).IsEqualTo(true)
+	assert.With(t).That(isTransient(ErrUnknown)).IsEqualTo(true)
+	assert.With(t).That(isTransient(ErrDownloadLimitReached)).IsEqualTo(false)
+	assert.With(t).That(isTransient(ErrNoSuchItem)).IsEqualTo(false)
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	for i := 0; i < 20; i++ {
+		d := p.delay(1, nil)
+		assert.With(t).That(d >= 500*time.Millisecond && d <= time.Second).IsEqualTo(true)
+
+		d = p.delay(2, nil)
+		assert.With(t).That(d >= time.Second && d <= 2*time.Second).IsEqualTo(true)
+
+		d = p.delay(10, nil)
+		assert.With(t).That(d >= 1500*time.Millisecond && d <= 3*time.Second).IsEqualTo(true)
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", "120")
+	assert.With(t).That(int64(p.delay(1, header))).IsEqualTo(int64(2 * time.Minute))
+
+	header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.With(t).That(int64(p.delay(1, header))).IsEqualTo(int64(0))
+}
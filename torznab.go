@@ -0,0 +1,99 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// TorrentInfo contains the torznab attributes that describe a torrent.
+type TorrentInfo struct {
+	Seeders              int
+	Peers                int
+	InfoHash             string
+	MagnetUrl            string
+	DownloadVolumeFactor float64
+	UploadVolumeFactor   float64
+	MinimumRatio         float64
+	MinimumSeedTime      time.Duration
+}
+
+// IsTorrent reports whether the item is a torrent rather than an NZB.
+func (i Item) IsTorrent() bool {
+	if i.Enclosure.Type == "application/x-bittorrent" {
+		return true
+	}
+	for _, attr := range i.Attr {
+		if attr.IsTorznab() {
+			return true
+		}
+	}
+	return false
+}
+
+// Torrent returns the torrent attributes of the item. The volume factors default to 1, as the Torznab specification
+// requires when they are not sent.
+func (i Item) Torrent() TorrentInfo {
+	seedTime, _ := strconv.ParseInt(i.AttrValue("minimumseedtime"), 10, 64)
+
+	return TorrentInfo{
+		Seeders:              i.intAttr("seeders"),
+		Peers:                i.intAttr("peers"),
+		InfoHash:             i.AttrValue("infohash"),
+		MagnetUrl:            i.AttrValue("magneturl"),
+		DownloadVolumeFactor: i.floatAttr("downloadvolumefactor", 1),
+		UploadVolumeFactor:   i.floatAttr("uploadvolumefactor", 1),
+		MinimumRatio:         i.floatAttr("minimumratio", 0),
+		MinimumSeedTime:      time.Duration(seedTime) * time.Second,
+	}
+}
+
+// floatAttr returns the value of a decimal attribute, or def if it isn't present.
+func (i Item) floatAttr(name string, def float64) float64 {
+	f, err := strconv.ParseFloat(i.AttrValue(name), 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// GetTorrent retrieves a .torrent file from a Torznab server.
+func (c *Client) GetTorrent(id string) ([]byte, error) {
+	return c.GetTorrentContext(context.Background(), id)
+}
+
+// GetTorrentContext retrieves a .torrent file from a Torznab server using the provided context.
+func (c *Client) GetTorrentContext(ctx context.Context, id string) ([]byte, error) {
+	res, err := c.call(ctx, Apikey(c.key), nzbid(id), Type("get"))
+	if err != nil {
+		return nil, err
+	}
+
+	return res.body, nil
+}
+
+// GetTorrent retrieves a .torrent file from a Torznab server.
+func GetTorrent(url string, key string, id string) ([]byte, error) {
+	return newClient(url, key).GetTorrent(id)
+}
+
+// GetTorrentContext retrieves a .torrent file from a Torznab server using the provided context.
+func GetTorrentContext(ctx context.Context, url string, key string, id string) ([]byte, error) {
+	return newClient(url, key).GetTorrentContext(ctx, id)
+}
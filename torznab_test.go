@@ -0,0 +1,73 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTorznabFromXml(t *testing.T) {
+	original, err := ioutil.ReadFile("testdata/torznab-results.xml")
+	if err != nil {
+		t.Error(err)
+	}
+
+	feed, err := NewznabFromXml(original)
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(feed.Torznab).IsEqualTo(TorznabNamespace)
+
+	item := feed.Channel.Item[0]
+	assert.With(t).That(item.IsTorrent()).IsEqualTo(true)
+	assert.With(t).That(item.Size()).IsEqualTo(int64(2785017856))
+
+	torrent := item.Torrent()
+	assert.With(t).That(torrent.Seeders).IsEqualTo(1523)
+	assert.With(t).That(torrent.Peers).IsEqualTo(1601)
+	assert.With(t).That(torrent.InfoHash).IsEqualTo("9fc20b9e98ea98b4a35e6223041a5ef94ea27809")
+	assert.With(t).That(torrent.DownloadVolumeFactor).IsEqualTo(0.0)
+	assert.With(t).That(torrent.UploadVolumeFactor).IsEqualTo(1.0)
+	assert.With(t).That(torrent.MinimumRatio).IsEqualTo(1.0)
+	assert.With(t).That(int64(torrent.MinimumSeedTime)).IsEqualTo(int64(48 * time.Hour))
+
+	// The attributes keep their namespace when the feed is encoded again.
+	data, err := feed.ToXml()
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(strings.Contains(string(data), `xmlns:torznab="`+TorznabNamespace+`"`)).IsEqualTo(true)
+	assert.With(t).That(strings.Count(string(data), "<torznab:attr ")).IsEqualTo(9)
+	assert.With(t).That(strings.Contains(string(data), "<newznab:attr ")).IsEqualTo(false)
+}
+
+func TestGetTorrent(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/x-bittorrent")
+		_, _ = w.Write([]byte("d8:announce0:e"))
+	}))
+	defer server.Close()
+
+	torrent, err := GetTorrent(server.URL, "key", "42")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(string(torrent)).IsEqualTo("d8:announce0:e")
+	assert.With(t).That(query).IsEqualTo("apikey=key&id=42&t=get")
+}
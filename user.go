@@ -0,0 +1,204 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"golang.org/x/net/html/charset"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// User describes the account that owns the API key, as returned by a "t=user" request.
+type User struct {
+	Username   string
+	Role       string
+	Grabs      int
+	GrabLimit  int
+	ApiHits    int
+	ApiLimit   int
+	Expires    time.Time
+	Attributes map[string]string
+}
+
+// Registration contains the credentials of a newly registered account.
+type Registration struct {
+	Username string
+	Password string
+	Apikey   string
+}
+
+// Names used by the various server implementations for the same user attributes.
+var (
+	userApiHitNames    = []string{"apirequests", "apihits", "api_requests"}
+	userApiLimitNames  = []string{"apilimit", "apirequestslimit", "api_limit"}
+	userGrabLimitNames = []string{"downloadlimit", "grablimit", "download_limit"}
+	userExpiresNames   = []string{"expires", "rolechangedate", "vip_expire_date", "expiry"}
+)
+
+// GetUser returns the usage and limits of the account that owns the API key.
+func (c *Client) GetUser() (*User, error) {
+	return c.GetUserContext(context.Background())
+}
+
+// GetUserContext returns the usage and limits of the account using the provided context.
+func (c *Client) GetUserContext(ctx context.Context) (*User, error) {
+	res, err := c.call(ctx, Apikey(c.key), Type("user"))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := rootAttrs("user", res.body)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		Username:   attrs["username"],
+		Role:       attrs["role"],
+		Grabs:      atoi(attrs["grabs"]),
+		GrabLimit:  atoi(firstAttr(attrs, userGrabLimitNames)),
+		ApiHits:    atoi(firstAttr(attrs, userApiHitNames)),
+		ApiLimit:   atoi(firstAttr(attrs, userApiLimitNames)),
+		Attributes: attrs,
+	}
+	user.Expires, _ = parseAnyDate(firstAttr(attrs, userExpiresNames))
+
+	return user, nil
+}
+
+// Register creates an account for the email address and returns its credentials. Servers that don't allow
+// registration return errors that match ErrRegistrationDenied, ErrRegistrationClosed, ErrEmailTaken, ErrEmailInvalid
+// or ErrRegistrationFailed.
+func (c *Client) Register(email string) (*Registration, error) {
+	return c.RegisterContext(context.Background(), email)
+}
+
+// RegisterContext creates an account for the email address using the provided context.
+func (c *Client) RegisterContext(ctx context.Context, email string) (*Registration, error) {
+	res, err := c.call(ctx, Param{Name: "email", Value: email}, Type("register"))
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := rootAttrs("register", res.body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registration{
+		Username: attrs["username"],
+		Password: attrs["password"],
+		Apikey:   attrs["apikey"],
+	}, nil
+}
+
+// GetUser returns the usage and limits of the account that owns the API key.
+func GetUser(url string, key string) (*User, error) {
+	return newClient(url, key).GetUser()
+}
+
+// GetUserContext returns the usage and limits of the account using the provided context.
+func GetUserContext(ctx context.Context, url string, key string) (*User, error) {
+	return newClient(url, key).GetUserContext(ctx)
+}
+
+// Register creates an account for the email address and returns its credentials.
+func Register(url string, email string) (*Registration, error) {
+	return newClient(url, "").Register(email)
+}
+
+// RegisterContext creates an account for the email address using the provided context.
+func RegisterContext(ctx context.Context, url string, email string) (*Registration, error) {
+	return newClient(url, "").RegisterContext(ctx, email)
+}
+
+// rootAttrs returns the attributes of a response that consists of a single element, such as <user username="..."/>,
+// in either XML or JSON format.
+func rootAttrs(name string, body []byte) (map[string]string, error) {
+	attrs := make(map[string]string)
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		obj := jsonObject(doc)
+		if inner := jsonObject(obj[name]); inner != nil {
+			obj = inner
+		}
+		if wrapped := jsonObject(obj["@attributes"]); wrapped != nil {
+			obj = wrapped
+		}
+		for k, v := range obj {
+			attrs[strings.TrimLeft(k, "@_")] = jsonText(v)
+		}
+		return attrs, nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charset.NewReaderLabel
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, errors.New("newznab: response has no <" + name + "> element")
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != name {
+				return nil, errors.New("newznab: expected <" + name + "> but received <" + start.Name.Local + ">")
+			}
+			for _, attr := range start.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+			return attrs, nil
+		}
+	}
+}
+
+// firstAttr returns the value of the first of the names present in the attributes.
+func firstAttr(attrs map[string]string, names []string) string {
+	for _, name := range names {
+		if v, ok := attrs[name]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// atoi converts a string to an int, returning zero if it isn't a number.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
+
+// parseAnyDate parses an RSS date, or the SQL-style dates servers use for account details.
+func parseAnyDate(s string) (time.Time, error) {
+	if t, err := parseDate(s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02 15:04:05", strings.TrimSpace(s))
+}
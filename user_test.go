@@ -0,0 +1,81 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"errors"
+	"github.com/MediaExchange/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<user username="bob" role="VIP" grabs="7" apirequests="42" apilimit="1000" downloadlimit="100" vip_expire_date="2021-06-30 00:00:00"/>`))
+	}))
+	defer server.Close()
+
+	user, err := GetUser(server.URL, "key")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(user.Username).IsEqualTo("bob")
+	assert.With(t).That(user.Role).IsEqualTo("VIP")
+	assert.With(t).That(user.Grabs).IsEqualTo(7)
+	assert.With(t).That(user.GrabLimit).IsEqualTo(100)
+	assert.With(t).That(user.ApiHits).IsEqualTo(42)
+	assert.With(t).That(user.ApiLimit).IsEqualTo(1000)
+	assert.With(t).That(user.Expires.Year()).IsEqualTo(2021)
+}
+
+func TestGetUserJson(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"user":{"@attributes":{"username":"bob","grabs":7,"apirequests":42}}}`))
+	}))
+	defer server.Close()
+
+	user, err := GetUser(server.URL, "key")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(user.Username).IsEqualTo("bob")
+	assert.With(t).That(user.ApiHits).IsEqualTo(42)
+}
+
+func TestRegister(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<register username="bob" password="secret" apikey="0123456789abcdef"/>`))
+	}))
+	defer server.Close()
+
+	reg, err := Register(server.URL, "bob@example.com")
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(query).IsEqualTo("email=bob%40example.com&t=register")
+	assert.With(t).That(reg.Username).IsEqualTo("bob")
+	assert.With(t).That(reg.Password).IsEqualTo("secret")
+	assert.With(t).That(reg.Apikey).IsEqualTo("0123456789abcdef")
+}
+
+func TestRegisterDenied(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<error code="105" description="Invalid registration (Email Address Taken)"/>`))
+	}))
+	defer server.Close()
+
+	_, err := Register(server.URL, "bob@example.com")
+	assert.With(t).That(errors.Is(err, ErrEmailTaken)).IsEqualTo(true)
+}
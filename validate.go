@@ -0,0 +1,222 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidationMode controls how a Client checks search parameters against the server's capabilities.
+type ValidationMode int
+
+const (
+	// ValidateOff sends parameters to the server without checking them. This is the default.
+	ValidateOff ValidationMode = iota
+
+	// ValidateStrict returns a *ValidationError instead of sending a search with unsupported parameters.
+	ValidateStrict
+
+	// ValidateDrop removes unsupported parameters, lowers the limit to the server's maximum and sends the search. The
+	// changes are reported to the handler set by WithValidationHandler.
+	ValidateDrop
+)
+
+// ValidationError describes why a search's parameters don't match the server's capabilities.
+type ValidationError struct {
+	// SearchType is the value of the "t" parameter, such as "tvsearch".
+	SearchType string
+
+	// Unavailable is set when the server doesn't support the type of search at all.
+	Unavailable bool
+
+	// Unsupported contains the parameters that aren't listed in the server's supportedParams.
+	Unsupported []Param
+
+	// Limit is the requested number of results when it is greater than MaxLimit.
+	Limit int
+
+	// MaxLimit is the maximum number of results the server will return.
+	MaxLimit int
+}
+
+// Error describes each of the problems found.
+func (e *ValidationError) Error() string {
+	if e.Unavailable {
+		return fmt.Sprintf("newznab: %s is not available on this server", e.SearchType)
+	}
+
+	problems := make([]string, 0)
+	if len(e.Unsupported) > 0 {
+		names := make([]string, len(e.Unsupported))
+		for i, p := range e.Unsupported {
+			names[i] = p.Name
+		}
+		problems = append(problems, "unsupported parameters "+strings.Join(names, ","))
+	}
+	if e.Limit > 0 {
+		problems = append(problems, fmt.Sprintf("limit %d exceeds the maximum of %d", e.Limit, e.MaxLimit))
+	}
+
+	return fmt.Sprintf("newznab: invalid %s: %s", e.SearchType, strings.Join(problems, "; "))
+}
+
+// WithCaps returns an Option that provides the server's capabilities for validation, rather than having the Client
+// request them before the first search.
+func WithCaps(caps *Caps) Option {
+	return func(c *Client) {
+		c.caps = caps
+	}
+}
+
+// WithValidation returns an Option that checks search parameters against the server's capabilities.
+func WithValidation(mode ValidationMode) Option {
+	return func(c *Client) {
+		c.validation = mode
+	}
+}
+
+// WithValidationHandler returns an Option that receives the changes made to a search when the ValidateDrop mode is
+// used.
+func WithValidationHandler(h func(*ValidationError)) Option {
+	return func(c *Client) {
+		c.validationHandler = h
+	}
+}
+
+// Capabilities returns the server's capabilities. They are requested once and reused for the life of the Client.
+func (c *Client) Capabilities(ctx context.Context) (*Caps, error) {
+	c.capsMu.Lock()
+	defer c.capsMu.Unlock()
+
+	if c.caps == nil {
+		caps, err := c.GetCapabilitiesParsedContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.caps = caps
+	}
+	return c.caps, nil
+}
+
+// ForType returns the capabilities of the type of search named by the "t" parameter.
+func (s CapsSearching) ForType(t string) (CapsSearch, bool) {
+	switch t {
+	case "search":
+		return s.Search, true
+	case "tvsearch":
+		return s.TvSearch, true
+	case "movie":
+		return s.MovieSearch, true
+	case "music":
+		return s.AudioSearch, true
+	case "book":
+		return s.BookSearch, true
+	}
+	return CapsSearch{}, false
+}
+
+// validate checks the parameters of a search of type "t" and returns the parameters to send.
+func (c *Client) validate(ctx context.Context, t string, params []Param) ([]Param, error) {
+	if c.validation == ValidateOff {
+		return params, nil
+	}
+
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	verr, valid := validateParams(caps, t, params)
+	if verr == nil {
+		return params, nil
+	}
+
+	if verr.Unavailable || c.validation == ValidateStrict {
+		return nil, verr
+	}
+
+	if c.validationHandler != nil {
+		c.validationHandler(verr)
+	}
+	return valid, nil
+}
+
+// validateParams compares the parameters to the capabilities. It returns any problems found, along with the
+// parameters with those problems removed.
+func validateParams(caps *Caps, t string, params []Param) (*ValidationError, []Param) {
+	search, ok := caps.Searching.ForType(t)
+	if !ok {
+		return nil, params
+	}
+
+	verr := &ValidationError{SearchType: t}
+	if !search.IsAvailable() {
+		verr.Unavailable = true
+		return verr, nil
+	}
+
+	valid := make([]Param, 0, len(params))
+	for _, p := range params {
+		switch {
+		case p.Name == "limit":
+			limit, err := strconv.Atoi(p.Value)
+			if err == nil && caps.Limits.Max > 0 && limit > caps.Limits.Max {
+				verr.Limit = limit
+				verr.MaxLimit = caps.Limits.Max
+				p = Limit(caps.Limits.Max)
+			}
+		case genericParams[p.Name]:
+		case !search.Supports(paramAlias(p.Name)):
+			verr.Unsupported = append(verr.Unsupported, p)
+			continue
+		}
+		valid = append(valid, p)
+	}
+
+	if len(verr.Unsupported) == 0 && verr.Limit == 0 {
+		return nil, params
+	}
+	return verr, valid
+}
+
+// genericParams are accepted by every type of search, so servers don't list them in supportedParams.
+var genericParams = map[string]bool{
+	"apikey":   true,
+	"attrs":    true,
+	"cat":      true,
+	"del":      true,
+	"extended": true,
+	"limit":    true,
+	"maxage":   true,
+	"maxsize":  true,
+	"minsize":  true,
+	"o":        true,
+	"offset":   true,
+	"t":        true,
+}
+
+// paramAlias returns the name a server uses in supportedParams for a parameter.
+func paramAlias(name string) string {
+	switch name {
+	case "episode":
+		return "ep"
+	}
+	return name
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2021 MediaExchange.io
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package newznab
+
+import (
+	"errors"
+	"github.com/MediaExchange/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testCaps(t *testing.T) *Caps {
+	data, err := ioutil.ReadFile("testdata/caps.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caps, err := CapsFromXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &caps
+}
+
+func TestValidateStrict(t *testing.T) {
+	c := NewClient(WithUrl("http://localhost/api"), WithCaps(testCaps(t)), WithValidation(ValidateStrict))
+
+	_, err := c.MovieSearch(Query("Terminator"), Season(2), Limit(500))
+	var verr *ValidationError
+	assert.With(t).That(errors.As(err, &verr)).IsEqualTo(true)
+	assert.With(t).That(len(verr.Unsupported)).IsEqualTo(1)
+	assert.With(t).That(verr.Unsupported[0].Name).IsEqualTo("season")
+	assert.With(t).That(verr.Limit).IsEqualTo(500)
+	assert.With(t).That(verr.MaxLimit).IsEqualTo(100)
+
+	_, err = c.BookSearch(Author("Tolkien"))
+	assert.With(t).That(errors.As(err, &verr)).IsEqualTo(true)
+	assert.With(t).That(verr.Unavailable).IsEqualTo(true)
+}
+
+func TestValidateDrop(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("t") == "caps" {
+			data, _ := ioutil.ReadFile("testdata/caps.xml")
+			_, _ = w.Write(data)
+			return
+		}
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte("<rss/>"))
+	}))
+	defer server.Close()
+
+	var reported *ValidationError
+	c := NewClient(WithUrl(server.URL), WithApikey("key"), WithValidation(ValidateDrop),
+		WithValidationHandler(func(verr *ValidationError) { reported = verr }))
+
+	_, err := c.TvSearch(Query("The Office"), Season(2), Episode(22), ImdbId(386676), Limit(500))
+	assert.With(t).That(err).IsNil()
+	assert.With(t).That(query).IsEqualTo("apikey=key&episode=E22&extended=1&limit=100&q=The+Office&season=S02&t=tvsearch")
+	assert.With(t).That(reported.Unsupported[0].Name).IsEqualTo("imdbid")
+}